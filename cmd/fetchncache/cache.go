@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+
+	"github.com/BorisAnthony/go-fetchncache/storage"
+)
+
+// CacheMeta records the validators and bookkeeping needed to make a
+// conditional GET on the next run.
+type CacheMeta struct {
+	ETag          string    `json:"etag,omitempty"`
+	LastModified  string    `json:"last_modified,omitempty"`
+	ContentLength int64     `json:"content_length"`
+	FetchedAt     time.Time `json:"fetched_at"`
+}
+
+// cacheMetaPath returns the sidecar path for a resolved target path. When
+// cacheDir is set, sidecars are kept together there (named after the base
+// filename) on local disk instead of next to the cached artifact. This is
+// also the key used in the target's own Storage when cacheDir is unset,
+// so the two agree on where a sidecar lives.
+func cacheMetaPath(resolvedPath, cacheDir string) string {
+	if cacheDir != "" {
+		return filepath.Join(cacheDir, filepath.Base(resolvedPath)+".meta")
+	}
+	return resolvedPath + ".meta"
+}
+
+// loadCacheMeta reads a target's sidecar, if present. A missing sidecar is
+// not an error: it just means there's nothing to validate against yet.
+//
+// cacheDir is an explicit opt-in to keep sidecars on local disk regardless
+// of the target's storage backend (e.g. a fast local cache in front of a
+// slower object store). Left unset, the sidecar is read through the same
+// store.Storage the target's data goes through, so a target backed by
+// S3/GCS/Redis doesn't silently depend on a writable local filesystem.
+func loadCacheMeta(store storage.Storage, cacheDir, resolvedPath string) (*CacheMeta, error) {
+	if cacheDir != "" {
+		return loadCacheMetaFile(cacheMetaPath(resolvedPath, cacheDir))
+	}
+	return loadCacheMetaFromStore(store, cacheMetaPath(resolvedPath, ""))
+}
+
+func loadCacheMetaFile(path string) (*CacheMeta, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading cache meta %q: %w", path, err)
+	}
+	return unmarshalCacheMeta(path, data)
+}
+
+func loadCacheMetaFromStore(store storage.Storage, key string) (*CacheMeta, error) {
+	exists, err := store.Exists(key)
+	if err != nil {
+		return nil, fmt.Errorf("checking cache meta %q: %w", key, err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	data, _, err := store.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("reading cache meta %q: %w", key, err)
+	}
+	return unmarshalCacheMeta(key, data)
+}
+
+func unmarshalCacheMeta(source string, data []byte) (*CacheMeta, error) {
+	var meta CacheMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("parsing cache meta %q: %w", source, err)
+	}
+	return &meta, nil
+}
+
+// saveCacheMeta writes a target's sidecar. See loadCacheMeta for how
+// cacheDir picks between local disk and the target's Storage.
+func saveCacheMeta(store storage.Storage, cacheDir, resolvedPath string, meta CacheMeta) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding cache meta: %w", err)
+	}
+
+	if cacheDir != "" {
+		return writeFileWithDir(cacheMetaPath(resolvedPath, cacheDir), data)
+	}
+	return store.Put(cacheMetaPath(resolvedPath, ""), data, nil)
+}
+
+// applyConditionalHeaders sets If-None-Match / If-Modified-Since on req
+// based on a previously recorded CacheMeta, if any validators are present.
+func applyConditionalHeaders(req *retryablehttp.Request, meta *CacheMeta) {
+	if meta == nil {
+		return
+	}
+	if meta.ETag != "" {
+		req.Header.Set("If-None-Match", meta.ETag)
+	}
+	if meta.LastModified != "" {
+		req.Header.Set("If-Modified-Since", meta.LastModified)
+	}
+}
+
+// cacheMetaFromResponse builds a CacheMeta from the response headers of a
+// successful (non-304) fetch.
+func cacheMetaFromResponse(resp *http.Response, contentLength int64) CacheMeta {
+	return CacheMeta{
+		ETag:          resp.Header.Get("ETag"),
+		LastModified:  resp.Header.Get("Last-Modified"),
+		ContentLength: contentLength,
+		FetchedAt:     time.Now(),
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+
+	"github.com/BorisAnthony/go-fetchncache/storage"
+)
+
+func TestCacheMetaPath(t *testing.T) {
+	if got, want := cacheMetaPath("out/data.json", ""), "out/data.json.meta"; got != want {
+		t.Errorf("cacheMetaPath(%q, \"\") = %q, want %q", "out/data.json", got, want)
+	}
+
+	got := cacheMetaPath("out/data.json", "cache")
+	want := filepath.Join("cache", "data.json.meta")
+	if got != want {
+		t.Errorf("cacheMetaPath with cacheDir = %q, want %q", got, want)
+	}
+}
+
+func TestLoadCacheMetaMissingFile(t *testing.T) {
+	cacheDir := t.TempDir()
+	store, err := storage.New("fs", nil)
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+
+	meta, err := loadCacheMeta(store, cacheDir, filepath.Join(t.TempDir(), "absent.json"))
+	if err != nil {
+		t.Fatalf("loadCacheMeta: %v", err)
+	}
+	if meta != nil {
+		t.Errorf("loadCacheMeta on a missing file = %+v, want nil", meta)
+	}
+}
+
+func TestSaveAndLoadCacheMetaRoundTripWithCacheDir(t *testing.T) {
+	cacheDir := t.TempDir()
+	resolvedPath := filepath.Join(t.TempDir(), "data.json")
+	store, err := storage.New("fs", nil)
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	want := CacheMeta{ETag: `"abc123"`, LastModified: "Wed, 21 Oct 2015 07:28:00 GMT", ContentLength: 42}
+
+	if err := saveCacheMeta(store, cacheDir, resolvedPath, want); err != nil {
+		t.Fatalf("saveCacheMeta: %v", err)
+	}
+
+	got, err := loadCacheMeta(store, cacheDir, resolvedPath)
+	if err != nil {
+		t.Fatalf("loadCacheMeta: %v", err)
+	}
+	if got == nil {
+		t.Fatal("loadCacheMeta returned nil after a successful save")
+	}
+	if got.ETag != want.ETag || got.LastModified != want.LastModified || got.ContentLength != want.ContentLength {
+		t.Errorf("loadCacheMeta = %+v, want %+v", *got, want)
+	}
+}
+
+// TestSaveAndLoadCacheMetaThroughStore covers the default (cacheDir == "")
+// path: the sidecar must go through the target's own Storage, not straight
+// to the local filesystem, so a memory/S3/GCS/Redis-backed target doesn't
+// depend on a writable local disk just for conditional GET bookkeeping.
+func TestSaveAndLoadCacheMetaThroughStore(t *testing.T) {
+	store, err := storage.New("memory", nil)
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	resolvedPath := "some/key/data.json"
+	want := CacheMeta{ETag: `"abc123"`, ContentLength: 42}
+
+	if err := saveCacheMeta(store, "", resolvedPath, want); err != nil {
+		t.Fatalf("saveCacheMeta: %v", err)
+	}
+
+	if exists, err := store.Exists("some/key/data.json.meta"); err != nil || !exists {
+		t.Fatalf("sidecar was not written to the store under the expected key: exists=%v err=%v", exists, err)
+	}
+
+	got, err := loadCacheMeta(store, "", resolvedPath)
+	if err != nil {
+		t.Fatalf("loadCacheMeta: %v", err)
+	}
+	if got == nil {
+		t.Fatal("loadCacheMeta returned nil after a successful save")
+	}
+	if got.ETag != want.ETag || got.ContentLength != want.ContentLength {
+		t.Errorf("loadCacheMeta = %+v, want %+v", *got, want)
+	}
+}
+
+func TestApplyConditionalHeaders(t *testing.T) {
+	req, err := retryablehttp.NewRequest("GET", "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+
+	applyConditionalHeaders(req, &CacheMeta{ETag: `"abc"`, LastModified: "Wed, 21 Oct 2015 07:28:00 GMT"})
+
+	if got := req.Header.Get("If-None-Match"); got != `"abc"` {
+		t.Errorf("If-None-Match = %q, want %q", got, `"abc"`)
+	}
+	if got := req.Header.Get("If-Modified-Since"); got != "Wed, 21 Oct 2015 07:28:00 GMT" {
+		t.Errorf("If-Modified-Since = %q, want the recorded Last-Modified value", got)
+	}
+}
+
+func TestApplyConditionalHeadersNilMeta(t *testing.T) {
+	req, err := retryablehttp.NewRequest("GET", "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+
+	applyConditionalHeaders(req, nil)
+
+	if req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		t.Error("applyConditionalHeaders with a nil meta should not set any validators")
+	}
+}
+
+func TestCacheMetaFromResponse(t *testing.T) {
+	resp := &http.Response{Header: http.Header{
+		"Etag":          {`"xyz"`},
+		"Last-Modified": {"Wed, 21 Oct 2015 07:28:00 GMT"},
+	}}
+
+	before := time.Now()
+	meta := cacheMetaFromResponse(resp, 123)
+	if meta.ETag != `"xyz"` {
+		t.Errorf("ETag = %q, want %q", meta.ETag, `"xyz"`)
+	}
+	if meta.ContentLength != 123 {
+		t.Errorf("ContentLength = %d, want 123", meta.ContentLength)
+	}
+	if meta.FetchedAt.Before(before) {
+		t.Error("FetchedAt should be set to (approximately) now")
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+// validCompressionCodecs lists the codecs accepted by both
+// config.compression and a target's compression override.
+var validCompressionCodecs = []string{"", "none", "gzip", "zstd", "lz4"}
+
+// validateCompression checks a codec name at config-load time.
+func validateCompression(codec string) error {
+	for _, valid := range validCompressionCodecs {
+		if codec == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q must be one of none, gzip, zstd, lz4", codec)
+}
+
+// resolveCompression picks a target's codec: its own override, else the
+// run's default, else "none" - mirroring resolveStorage's precedence.
+func resolveCompression(target Target, opts runOptions) string {
+	if target.Compression != "" {
+		return target.Compression
+	}
+	if opts.Compression != "" {
+		return opts.Compression
+	}
+	return "none"
+}
+
+// compressionExtension is the suffix appended to a resolved path when
+// codec is used, so a compressed cache entry can be told apart (and
+// decompressed) without consulting the config.
+func compressionExtension(codec string) string {
+	switch codec {
+	case "gzip":
+		return ".gz"
+	case "zstd":
+		return ".zst"
+	case "lz4":
+		return ".lz4"
+	default:
+		return ""
+	}
+}
+
+// codecFromExtension infers a codec from a file's extension, for the
+// `decompress` subcommand.
+func codecFromExtension(path string) string {
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		return "gzip"
+	case strings.HasSuffix(path, ".zst"):
+		return "zstd"
+	case strings.HasSuffix(path, ".lz4"):
+		return "lz4"
+	default:
+		return ""
+	}
+}
+
+// compressBytes encodes data with the named codec. "" and "none" return
+// data unchanged, so call sites don't need to branch on whether
+// compression is configured.
+func compressBytes(codec string, data []byte) ([]byte, error) {
+	switch codec {
+	case "", "none":
+		return data, nil
+
+	case "gzip":
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("gzip: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("gzip: %w", err)
+		}
+		return buf.Bytes(), nil
+
+	case "zstd":
+		enc, err := zstd.NewWriter(nil)
+		if err != nil {
+			return nil, fmt.Errorf("zstd: %w", err)
+		}
+		defer enc.Close()
+		return enc.EncodeAll(data, nil), nil
+
+	case "lz4":
+		var buf bytes.Buffer
+		w := lz4.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("lz4: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("lz4: %w", err)
+		}
+		return buf.Bytes(), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported compression codec %q", codec)
+	}
+}
+
+// decompressBytes reverses compressBytes for the named codec.
+func decompressBytes(codec string, data []byte) ([]byte, error) {
+	switch codec {
+	case "", "none":
+		return data, nil
+
+	case "gzip":
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("gzip: %w", err)
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+
+	case "zstd":
+		dec, err := zstd.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("zstd: %w", err)
+		}
+		defer dec.Close()
+		return io.ReadAll(dec)
+
+	case "lz4":
+		return io.ReadAll(lz4.NewReader(bytes.NewReader(data)))
+
+	default:
+		return nil, fmt.Errorf("unsupported compression codec %q", codec)
+	}
+}
+
+// runDecompress implements the `fetchncache decompress <path>`
+// subcommand: it infers the codec from the file's extension and writes
+// the decompressed bytes to stdout for on-disk inspection.
+func runDecompress(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: fetchncache decompress <path>")
+	}
+
+	path := args[0]
+	codec := codecFromExtension(path)
+	if codec == "" {
+		return fmt.Errorf("could not infer compression codec from extension of %q (expected .gz, .zst, or .lz4)", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %q: %w", path, err)
+	}
+
+	decoded, err := decompressBytes(codec, data)
+	if err != nil {
+		return fmt.Errorf("decompressing %q: %w", path, err)
+	}
+
+	_, err = os.Stdout.Write(decoded)
+	return err
+}
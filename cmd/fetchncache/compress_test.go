@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// benchmarkJSON approximates a representative feed: many repeated small
+// records, which is where compression pays off most.
+var benchmarkJSON = []byte(`{"items":[` +
+	strings.Repeat(`{"id":1,"name":"example","tags":["a","b","c"],"active":true},`, 500) +
+	`{"id":1,"name":"example","tags":["a","b","c"],"active":true}]}`)
+
+func TestCompressRoundTrip(t *testing.T) {
+	for _, codec := range []string{"none", "gzip", "zstd", "lz4"} {
+		compressed, err := compressBytes(codec, benchmarkJSON)
+		if err != nil {
+			t.Fatalf("%s: compress: %v", codec, err)
+		}
+		decompressed, err := decompressBytes(codec, compressed)
+		if err != nil {
+			t.Fatalf("%s: decompress: %v", codec, err)
+		}
+		if string(decompressed) != string(benchmarkJSON) {
+			t.Fatalf("%s: round trip did not return the original bytes", codec)
+		}
+	}
+}
+
+func TestGenerateLatestPathWithCompression(t *testing.T) {
+	cases := map[string]string{
+		"cache/data.json":        "cache/latest.json",
+		"cache/data.json.gz":     "cache/latest.json.gz",
+		"cache/data.pp.json.zst": "cache/latest.pp.json.zst",
+		"cache/data.bin.lz4":     "cache/latest.bin.lz4",
+	}
+	for in, want := range cases {
+		if got := generateLatestPath(in); got != want {
+			t.Errorf("generateLatestPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// BenchmarkCompressGzip, BenchmarkCompressZstd, and BenchmarkCompressLz4
+// report each codec's time/op and (via pct_of_original) its compression
+// ratio on the same representative JSON payload, so `go test -bench .
+// -benchtime 1x` gives a quick size/CPU comparison across the three.
+func BenchmarkCompressGzip(b *testing.B) { benchmarkCompress(b, "gzip") }
+func BenchmarkCompressZstd(b *testing.B) { benchmarkCompress(b, "zstd") }
+func BenchmarkCompressLz4(b *testing.B)  { benchmarkCompress(b, "lz4") }
+
+func benchmarkCompress(b *testing.B, codec string) {
+	for i := 0; i < b.N; i++ {
+		if _, err := compressBytes(codec, benchmarkJSON); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	out, err := compressBytes(codec, benchmarkJSON)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportMetric(float64(len(out))/float64(len(benchmarkJSON))*100, "pct_of_original/op")
+}
@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func TestResolveConcurrency(t *testing.T) {
+	cases := []struct {
+		name        string
+		flagValue   int
+		configValue int
+		want        int
+	}{
+		{"flag wins", 8, 2, 8},
+		{"config used when no flag", 0, 2, 2},
+		{"default when neither set", 0, 0, defaultConcurrency},
+		{"flag wins over config even when config is larger", 2, 8, 2},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := resolveConcurrency(c.flagValue, c.configValue); got != c.want {
+				t.Errorf("resolveConcurrency(%d, %d) = %d, want %d", c.flagValue, c.configValue, got, c.want)
+			}
+		})
+	}
+}
+
+func TestResolveTargetStoresIsolatesPerTargetFailures(t *testing.T) {
+	config := Config{
+		Targets: []Target{
+			{Name: "good"},
+			{Name: "bad", Storage: &StorageConfig{Driver: "s3", Settings: map[string]string{}}},
+			{Name: "also-good"},
+		},
+	}
+
+	stores, errs := resolveTargetStores(config)
+
+	if _, ok := stores["good"]; !ok {
+		t.Error("target \"good\" should have resolved a store")
+	}
+	if _, ok := stores["also-good"]; !ok {
+		t.Error("target \"also-good\" should have resolved a store")
+	}
+	if _, ok := stores["bad"]; ok {
+		t.Error("target \"bad\" should not have a store, its storage config is invalid")
+	}
+	if _, ok := errs["bad"]; !ok {
+		t.Error("target \"bad\" should be reported in errs")
+	}
+	if len(errs) != 1 {
+		t.Errorf("errs = %v, want exactly one failing target", errs)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/robfig/cron/v3"
+)
+
+// cronParser accepts the conventional 5-field cron expressions used by
+// both Target.Cron and Config.DefaultSchedule.
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// runDaemon keeps fetchncache alive, running each target on its own cron
+// schedule (falling back to config.DefaultSchedule, or a single run at
+// startup if neither is set) until SIGINT/SIGTERM is received.
+func runDaemon(config Config, retryClient *retryablehttp.Client, opts runOptions) error {
+	fileLogger, consoleLogger := opts.FileLogger, opts.ConsoleLogger
+
+	// Resolve every target's Storage once, at startup, instead of on each
+	// cron fire - a long-running daemon would otherwise build (and never
+	// close) a fresh S3/GCS/Redis client every time a target's schedule
+	// ticks. A target whose storage can't be resolved is reported and
+	// excluded from scheduling below rather than aborting the daemon, so
+	// one bad target doesn't take the rest down with it.
+	stores, storeErrs := resolveTargetStores(config)
+	if len(storeErrs) > 0 {
+		var failures []targetFailure
+		for _, target := range config.Targets {
+			err, ok := storeErrs[target.Name]
+			if !ok {
+				continue
+			}
+			fileLogger.Error("Failed to set up storage for target, excluding from schedule", "name", target.Name, "error", err)
+			failures = append(failures, targetFailure{Name: target.Name, URL: target.URL, Err: err, At: time.Now()})
+		}
+		opts.Notifier.NotifyFailures(failures)
+	}
+
+	var wg sync.WaitGroup
+	mutexes := make(map[string]*sync.Mutex, len(config.Targets))
+	for _, target := range config.Targets {
+		mutexes[target.Name] = &sync.Mutex{}
+	}
+
+	runOnce := func(target Target) {
+		mutex := mutexes[target.Name]
+		if !mutex.TryLock() {
+			if consoleLogger != nil {
+				consoleLogger.Warn("Skipping run, previous one still in flight", "name", target.Name)
+			}
+			return
+		}
+		defer mutex.Unlock()
+
+		store := stores[target.Name]
+		if _, err := processTarget(target, retryClient, store, opts); err != nil {
+			fileLogger.Error("Failed to process target", "name", target.Name, "url", target.URL, "error", err)
+			opts.Notifier.NotifyFailures([]targetFailure{{Name: target.Name, URL: target.URL, Err: err, At: time.Now()}})
+		} else if opts.Notifier.notifyOnSuccess() {
+			opts.Notifier.NotifySuccess(fmt.Sprintf("target %q completed successfully", target.Name))
+		}
+	}
+
+	c := cron.New(cron.WithParser(cronParser))
+	type scheduled struct {
+		target   Target
+		schedule string
+		entryID  cron.EntryID
+	}
+	var entries []scheduled
+
+	for _, target := range config.Targets {
+		if _, failed := storeErrs[target.Name]; failed {
+			continue
+		}
+
+		target := target
+		schedule := target.Cron
+		if schedule == "" {
+			schedule = config.DefaultSchedule
+		}
+
+		if schedule == "" {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				runOnce(target)
+			}()
+			continue
+		}
+
+		entryID, err := c.AddFunc(schedule, func() {
+			wg.Add(1)
+			defer wg.Done()
+			runOnce(target)
+		})
+		if err != nil {
+			return fmt.Errorf("target %q: invalid cron schedule %q: %w", target.Name, schedule, err)
+		}
+		entries = append(entries, scheduled{target: target, schedule: schedule, entryID: entryID})
+	}
+
+	c.Start()
+
+	// Next-fire times are only meaningful once the scheduler is running.
+	if consoleLogger != nil {
+		for _, e := range entries {
+			consoleLogger.Info("Scheduled target", "name", e.target.Name, "schedule", e.schedule, "next", c.Entry(e.entryID).Next)
+		}
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	<-sigCh
+
+	if consoleLogger != nil {
+		consoleLogger.Info("Shutting down, waiting for in-flight fetches to finish")
+	}
+	<-c.Stop().Done()
+	wg.Wait()
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// attemptTracker records the last retryablehttp attempt number seen for a
+// single request, threaded through via context so the shared client's
+// RequestLogHook stays concurrency-safe across the worker pool.
+type attemptTracker struct {
+	attempt int
+}
+
+type attemptCtxKey struct{}
+
+// withAttemptTracker attaches a fresh tracker to req's context and returns
+// it so the caller can read the final attempt count after the request.
+func withAttemptTracker(req *retryablehttp.Request) *attemptTracker {
+	t := &attemptTracker{}
+	req.Request = req.Request.WithContext(context.WithValue(req.Context(), attemptCtxKey{}, t))
+	return t
+}
+
+// attemptLogHook is installed once on the shared retryablehttp.Client; it
+// just records the attempt number onto whichever tracker the in-flight
+// request carries.
+func attemptLogHook(_ retryablehttp.Logger, req *http.Request, attempt int) {
+	if t, ok := req.Context().Value(attemptCtxKey{}).(*attemptTracker); ok {
+		t.attempt = attempt
+	}
+}
+
+// dumpRequestResponse writes one file per target per run under dumpDir,
+// recording method, URL, request headers, status, response headers, and
+// body. Non-text bodies are base64-encoded so the dump stays one file.
+func dumpRequestResponse(dumpDir, targetName string, req *http.Request, resp *http.Response, body []byte) error {
+	if err := os.MkdirAll(dumpDir, 0755); err != nil {
+		return fmt.Errorf("creating dump directory %q: %w", dumpDir, err)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- request ---\n%s %s\n", req.Method, req.URL.String())
+	for name, values := range req.Header {
+		for _, v := range values {
+			fmt.Fprintf(&sb, "%s: %s\n", name, v)
+		}
+	}
+
+	fmt.Fprintf(&sb, "\n--- response ---\nstatus: %s\n", resp.Status)
+	for name, values := range resp.Header {
+		for _, v := range values {
+			fmt.Fprintf(&sb, "%s: %s\n", name, v)
+		}
+	}
+
+	sb.WriteString("\n--- body ---\n")
+	if isTextContentType(resp.Header.Get("Content-Type")) {
+		sb.Write(body)
+	} else {
+		sb.WriteString("base64:\n")
+		sb.WriteString(base64.StdEncoding.EncodeToString(body))
+	}
+
+	fileName := fmt.Sprintf("%s-%s.dump", sanitizeFileName(targetName), time.Now().UTC().Format("20060102T150405.000000000Z"))
+	return os.WriteFile(filepath.Join(dumpDir, fileName), []byte(sb.String()), 0644)
+}
+
+// isTextContentType reports whether a Content-Type looks safe to embed
+// verbatim rather than base64-encoding.
+func isTextContentType(contentType string) bool {
+	ct := strings.ToLower(contentType)
+	switch {
+	case strings.HasPrefix(ct, "text/"):
+		return true
+	case strings.Contains(ct, "json"):
+		return true
+	case strings.Contains(ct, "xml"):
+		return true
+	default:
+		return ct == ""
+	}
+}
+
+// sanitizeFileName makes a target name safe to use as (part of) a file name.
+func sanitizeFileName(name string) string {
+	if name == "" {
+		return "target"
+	}
+	replacer := strings.NewReplacer("/", "_", "\\", "_", " ", "_", ":", "_")
+	return replacer.Replace(name)
+}
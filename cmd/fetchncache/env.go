@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// envVarPattern matches a $ENV_NAME placeholder anywhere inside a string
+// field, e.g. the "$ENV_GITHUB_TOKEN" in "Bearer $ENV_GITHUB_TOKEN".
+var envVarPattern = regexp.MustCompile(`\$ENV_[A-Za-z0-9_]+`)
+
+// interpolateConfig rewrites every "$ENV_NAME" placeholder in config's
+// string fields (logfile, and each target's url, headers, and path
+// template) with the named environment variable's value. It runs at
+// load time, before path templates are expanded with {pattern}, so an
+// env-provided directory name still works with the pattern placeholder.
+//
+// A missing variable fails with an error naming the variable and target
+// index, unless allowMissingEnv is set, in which case it's substituted
+// with the empty string and reported back as a warning for the caller
+// to log once a logger exists.
+func interpolateConfig(config *Config, allowMissingEnv bool) ([]string, error) {
+	var warnings []string
+
+	resolve := func(s, where string) (string, error) {
+		resolved, missing, err := interpolateString(s, allowMissingEnv)
+		if err != nil {
+			return s, fmt.Errorf("%s: %w", where, err)
+		}
+		for _, name := range missing {
+			warnings = append(warnings, fmt.Sprintf("%s: %s not set, substituting empty string", where, name))
+		}
+		return resolved, nil
+	}
+
+	var err error
+	if config.LogFile, err = resolve(config.LogFile, "logfile"); err != nil {
+		return warnings, err
+	}
+
+	for i := range config.Targets {
+		target := &config.Targets[i]
+		where := fmt.Sprintf("target %d", i+1)
+
+		if target.URL, err = resolve(target.URL, where+": url"); err != nil {
+			return warnings, err
+		}
+
+		for j, header := range target.Headers {
+			if target.Headers[j], err = resolve(header, fmt.Sprintf("%s: headers[%d]", where, j)); err != nil {
+				return warnings, err
+			}
+		}
+
+		if err := interpolatePath(target, where, allowMissingEnv, &warnings); err != nil {
+			return warnings, err
+		}
+	}
+
+	return warnings, nil
+}
+
+// interpolatePath resolves env placeholders in a target's path, whether
+// it's a plain string or a pattern-based template.
+func interpolatePath(target *Target, where string, allowMissingEnv bool, warnings *[]string) error {
+	switch v := target.Path.(type) {
+	case string:
+		resolved, missing, err := interpolateString(v, allowMissingEnv)
+		if err != nil {
+			return fmt.Errorf("%s: path: %w", where, err)
+		}
+		*warnings = append(*warnings, prefixWarnings(missing, where+": path")...)
+		target.Path = resolved
+
+	case []interface{}:
+		if len(v) != 1 {
+			return nil // validateConfig reports the malformed shape
+		}
+		configMap, ok := v[0].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		template, ok := configMap["string"].(string)
+		if !ok {
+			return nil
+		}
+		resolved, missing, err := interpolateString(template, allowMissingEnv)
+		if err != nil {
+			return fmt.Errorf("%s: path.string: %w", where, err)
+		}
+		*warnings = append(*warnings, prefixWarnings(missing, where+": path.string")...)
+		configMap["string"] = resolved
+	}
+	return nil
+}
+
+// prefixWarnings turns a list of missing variable names into full
+// warning messages scoped to where they were found.
+func prefixWarnings(missing []string, where string) []string {
+	if len(missing) == 0 {
+		return nil
+	}
+	out := make([]string, len(missing))
+	for i, name := range missing {
+		out[i] = fmt.Sprintf("%s: %s not set, substituting empty string", where, name)
+	}
+	return out
+}
+
+// interpolateString replaces every $ENV_NAME placeholder in s with
+// os.Getenv's result. Unset variables are a hard error unless
+// allowMissingEnv is set, in which case they're replaced with "" and
+// their names are returned so the caller can warn about them.
+func interpolateString(s string, allowMissingEnv bool) (string, []string, error) {
+	var missing []string
+	var firstErr error
+
+	resolved := envVarPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if firstErr != nil {
+			return match
+		}
+		name := match[len("$ENV_"):]
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			if !allowMissingEnv {
+				firstErr = fmt.Errorf("environment variable %q is not set", name)
+				return match
+			}
+			missing = append(missing, name)
+			return ""
+		}
+		return value
+	})
+
+	if firstErr != nil {
+		return s, nil, firstErr
+	}
+	return resolved, missing, nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInterpolateConfigHeaders(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "secret123")
+
+	config := Config{
+		Targets: []Target{
+			{URL: "https://example.com", Path: "out.json", Headers: []string{"Authorization: Bearer $ENV_GITHUB_TOKEN"}},
+		},
+	}
+
+	if _, err := interpolateConfig(&config, false); err != nil {
+		t.Fatalf("interpolateConfig: %v", err)
+	}
+
+	want := "Authorization: Bearer secret123"
+	if got := config.Targets[0].Headers[0]; got != want {
+		t.Errorf("header = %q, want %q", got, want)
+	}
+}
+
+func TestInterpolateConfigURL(t *testing.T) {
+	t.Setenv("USERID", "42")
+
+	config := Config{
+		Targets: []Target{
+			{URL: "https://api.example.com/$ENV_USERID/feed.json", Path: "out.json"},
+		},
+	}
+
+	if _, err := interpolateConfig(&config, false); err != nil {
+		t.Fatalf("interpolateConfig: %v", err)
+	}
+
+	want := "https://api.example.com/42/feed.json"
+	if got := config.Targets[0].URL; got != want {
+		t.Errorf("url = %q, want %q", got, want)
+	}
+}
+
+func TestInterpolateConfigPathTemplateBeforePatternExpansion(t *testing.T) {
+	t.Setenv("OUTDIR", "cache/nightly")
+
+	config := Config{
+		Targets: []Target{
+			{
+				URL: "https://example.com",
+				Path: []interface{}{
+					map[string]interface{}{
+						"string":  "$ENV_OUTDIR/{pattern}.json",
+						"pattern": "DateOnly-UTC-slug",
+					},
+				},
+			},
+		},
+	}
+
+	if _, err := interpolateConfig(&config, false); err != nil {
+		t.Fatalf("interpolateConfig: %v", err)
+	}
+
+	resolved, err := config.Targets[0].GetResolvedPath()
+	if err != nil {
+		t.Fatalf("GetResolvedPath: %v", err)
+	}
+
+	if !strings.HasPrefix(resolved, "cache/nightly/") {
+		t.Errorf("resolved path = %q, want prefix %q (env resolved before pattern expansion)", resolved, "cache/nightly/")
+	}
+	if strings.Contains(resolved, "{pattern}") || strings.Contains(resolved, "$ENV_") {
+		t.Errorf("resolved path = %q, placeholder left unresolved", resolved)
+	}
+}
+
+func TestInterpolateConfigMissingEnvFails(t *testing.T) {
+	config := Config{
+		Targets: []Target{
+			{URL: "https://example.com/$ENV_DEFINITELY_NOT_SET", Path: "out.json"},
+		},
+	}
+
+	if _, err := interpolateConfig(&config, false); err == nil {
+		t.Fatal("expected an error for an unset environment variable")
+	}
+}
+
+func TestInterpolateConfigAllowMissingEnv(t *testing.T) {
+	config := Config{
+		Targets: []Target{
+			{URL: "https://example.com/$ENV_DEFINITELY_NOT_SET/feed", Path: "out.json"},
+		},
+	}
+
+	warnings, err := interpolateConfig(&config, true)
+	if err != nil {
+		t.Fatalf("interpolateConfig with allowMissingEnv: %v", err)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %d: %v", len(warnings), warnings)
+	}
+
+	want := "https://example.com//feed"
+	if got := config.Targets[0].URL; got != want {
+		t.Errorf("url = %q, want %q", got, want)
+	}
+}
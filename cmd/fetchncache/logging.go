@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// levelTrace sits below slog.LevelDebug so --log-level trace can request
+// the per-target HTTP request/response dumps on top of debug-level logs.
+const levelTrace = slog.Level(-8)
+
+// parseLogLevel maps the --log-level flag values onto slog levels.
+func parseLogLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "error":
+		return slog.LevelError, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "trace":
+		return levelTrace, nil
+	default:
+		return 0, fmt.Errorf("unsupported log level %q (expected error, warn, info, debug, or trace)", level)
+	}
+}
+
+// newHandler builds a text or JSON slog.Handler at the given level.
+func newHandler(w io.Writer, format string, level slog.Level) (slog.Handler, error) {
+	opts := &slog.HandlerOptions{Level: level}
+	switch format {
+	case "", "text":
+		return slog.NewTextHandler(w, opts), nil
+	case "json":
+		return slog.NewJSONHandler(w, opts), nil
+	default:
+		return nil, fmt.Errorf("unsupported log format %q (expected text or json)", format)
+	}
+}
+
+// rotateLogPath inserts the given date, formatted as YYYYMMDD, before the
+// file extension, e.g. "fetchncache.log" -> "fetchncache-20240102.log".
+func rotateLogPath(path string, date time.Time) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	return fmt.Sprintf("%s-%s%s", base, date.Format("20060102"), ext)
+}
+
+// rotatingFileWriter is an io.Writer backed by a dated log file that reopens
+// itself under the next day's rotateLogPath the first time it is written to
+// after midnight. Without this, a --daemon run spanning multiple days would
+// keep appending to the file it opened at startup forever.
+type rotatingFileWriter struct {
+	basePath string
+
+	mu   sync.Mutex
+	file *os.File
+	date string
+}
+
+// newRotatingFileWriter opens basePath's rotated path for now, creating its
+// directory if needed.
+func newRotatingFileWriter(basePath string, now time.Time) (*rotatingFileWriter, error) {
+	w := &rotatingFileWriter{basePath: basePath}
+	if err := w.openFor(now); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *rotatingFileWriter) openFor(now time.Time) error {
+	path := rotateLogPath(w.basePath, now)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating log directory %q: %w", dir, err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("opening log file %q: %w", path, err)
+	}
+
+	w.file = file
+	w.date = now.Format("20060102")
+	return nil
+}
+
+// Write rotates to the current day's file first if the date has rolled over
+// since the last write.
+func (w *rotatingFileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	if now.Format("20060102") != w.date {
+		old := w.file
+		if err := w.openFor(now); err != nil {
+			// Keep logging to the already-open file rather than losing the
+			// entry outright if today's path can't be opened (e.g. the disk
+			// is full or the directory became unwritable).
+			w.file = old
+			return old.Write(p)
+		}
+		old.Close()
+	}
+
+	return w.file.Write(p)
+}
+
+// Close closes the currently-open underlying file.
+func (w *rotatingFileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRotateLogPath(t *testing.T) {
+	date := time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC)
+	if got, want := rotateLogPath("fetchncache.log", date), "fetchncache-20240102.log"; got != want {
+		t.Errorf("rotateLogPath = %q, want %q", got, want)
+	}
+	if got, want := rotateLogPath("/var/log/fetchncache.log", date), "/var/log/fetchncache-20240102.log"; got != want {
+		t.Errorf("rotateLogPath = %q, want %q", got, want)
+	}
+}
+
+func TestNewRotatingFileWriterOpensTodaysPath(t *testing.T) {
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, "fetchncache.log")
+	now := time.Now()
+
+	w, err := newRotatingFileWriter(basePath, now)
+	if err != nil {
+		t.Fatalf("newRotatingFileWriter: %v", err)
+	}
+	defer w.Close()
+
+	wantPath := rotateLogPath(basePath, now)
+	if w.file.Name() != wantPath {
+		t.Errorf("opened file %q, want %q", w.file.Name(), wantPath)
+	}
+}
+
+func TestRotatingFileWriterRotatesOnDateChange(t *testing.T) {
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, "fetchncache.log")
+	now := time.Now()
+
+	w, err := newRotatingFileWriter(basePath, now)
+	if err != nil {
+		t.Fatalf("newRotatingFileWriter: %v", err)
+	}
+	defer w.Close()
+
+	// Simulate the writer having been opened on a stale day: the next Write
+	// should detect today's date no longer matches and reopen under today's
+	// path instead of continuing to append to the old one forever.
+	staleFile := w.file
+	w.date = "20000101"
+
+	if _, err := w.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	wantPath := rotateLogPath(basePath, now)
+	if w.file.Name() != wantPath {
+		t.Errorf("after rotation, writing to %q, want %q", w.file.Name(), wantPath)
+	}
+	if w.date != now.Format("20060102") {
+		t.Errorf("date = %q, want %q", w.date, now.Format("20060102"))
+	}
+
+	data, err := os.ReadFile(wantPath)
+	if err != nil {
+		t.Fatalf("reading rotated file: %v", err)
+	}
+	if string(data) != "hello\n" {
+		t.Errorf("rotated file contents = %q, want %q", data, "hello\n")
+	}
+
+	// The old handle should have been closed once rotation picked up a new
+	// one, rather than left open and leaking.
+	if err := staleFile.Close(); err == nil {
+		t.Error("expected the pre-rotation file handle to already be closed")
+	}
+}
@@ -1,20 +1,27 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
+	"github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/yaml.v3"
+
+	"github.com/BorisAnthony/go-fetchncache/storage"
+	"github.com/BorisAnthony/go-fetchncache/transform"
 )
 
 var version = "dev" // Will be overridden by build flags
@@ -27,18 +34,73 @@ type PathConfig struct {
 
 // Target represents a URL target from the YAML config
 type Target struct {
-	Name    string      `yaml:"name"`
-	URL     string      `yaml:"url"`
-	Path    interface{} `yaml:"path"` // Can be string or []PathConfig
-	Headers []string    `yaml:"headers,omitempty"`
+	Name        string          `yaml:"name"`
+	URL         string          `yaml:"url"`
+	Path        interface{}     `yaml:"path"` // Can be string or []PathConfig
+	Headers     []string        `yaml:"headers,omitempty"`
+	NoCache     bool            `yaml:"no_cache,omitempty"`    // skip conditional GET / sidecar for this target
+	RateLimit   string          `yaml:"rate_limit,omitempty"`  // minimum delay between requests to this target's host, e.g. "2s"
+	Storage     *StorageConfig  `yaml:"storage,omitempty"`     // overrides the global storage driver for this target
+	TTL         string          `yaml:"ttl,omitempty"`         // skip refetching until this long has elapsed since the last successful fetch, e.g. "6h"
+	Cron        string          `yaml:"cron,omitempty"`        // cron schedule for --daemon mode, e.g. "*/15 * * * *"; falls back to default_schedule, or runs once at startup if neither is set; ignored in one-shot runs
+	Transforms  []TransformSpec `yaml:"transforms,omitempty"`  // pipeline run on the fetched body before it's written; replaces json_format when set
+	Compression string          `yaml:"compression,omitempty"` // "none" (default), "gzip", "zstd", or "lz4"; overrides config.compression
+}
+
+// StorageConfig selects an output backend (see the storage package) and
+// carries its driver-specific settings.
+type StorageConfig struct {
+	Driver   string            `yaml:"driver"`
+	Settings map[string]string `yaml:"settings,omitempty"`
+}
+
+// TransformSpec names one step of a target's transform pipeline. It
+// accepts either a bare name (`transforms: [json_pretty]`) or a
+// single-key map carrying that step's argument (`transforms: [{duplicate: alt.json}]`).
+type TransformSpec struct {
+	Name string
+	Arg  string
+}
+
+func (t *TransformSpec) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		t.Name = value.Value
+		return nil
+	}
+
+	var m map[string]string
+	if err := value.Decode(&m); err != nil {
+		return fmt.Errorf("transform entry must be a string or a single-key map: %w", err)
+	}
+	if len(m) != 1 {
+		return fmt.Errorf("transform entry must have exactly one key, got %d", len(m))
+	}
+	for name, arg := range m {
+		t.Name, t.Arg = name, arg
+	}
+	return nil
 }
 
 // Config represents the YAML configuration structure
 type Config struct {
-	LogFile string   `yaml:"logfile"`
-	Targets []Target `yaml:"targets"`
+	LogFile         string              `yaml:"logfile"`
+	CacheDir        string              `yaml:"cache_dir,omitempty"`        // where .meta sidecars are kept; defaults to alongside each target's output file
+	MetaFormat      string              `yaml:"meta_format,omitempty"`      // sidecar format; only "json" is supported today
+	Concurrency     int                 `yaml:"concurrency,omitempty"`      // number of targets fetched in parallel; 0 means defaultConcurrency
+	Storage         StorageConfig       `yaml:"storage,omitempty"`          // default output backend; defaults to the "fs" driver
+	DumpDir         string              `yaml:"dump_dir,omitempty"`         // where trace-level request/response dumps are written
+	StateFile       string              `yaml:"state_file,omitempty"`       // tracks last-fetch time and content hash per target, for ttl
+	Metrics         MetricsConfig       `yaml:"metrics,omitempty"`          // optional Prometheus /metrics endpoint
+	DefaultSchedule string              `yaml:"default_schedule,omitempty"` // --daemon cron schedule used by targets without their own `cron`
+	Notifications   NotificationsConfig `yaml:"notifications,omitempty"`    // optional webhook/SMTP alerts on target failure
+	Compression     string              `yaml:"compression,omitempty"`      // default codec for all targets; see Target.Compression
+	Targets         []Target            `yaml:"targets"`
 }
 
+// defaultConcurrency is used when neither config.concurrency nor
+// --concurrency specify a worker pool size.
+const defaultConcurrency = 4
+
 // generatePatternValue generates a timestamp string based on the pattern
 func generatePatternValue(pattern string) (string, error) {
 	parts := strings.Split(pattern, "-")
@@ -80,7 +142,7 @@ func generatePatternValue(pattern string) (string, error) {
 	default:
 		return "", fmt.Errorf("unsupported datetime format: %s (supported: DateTime, DateOnly, TimeOnly, RFC3339, Kitchen, Stamp, DATETIME_SIMPLE_FS)", parts[0])
 	}
-	
+
 	formatted := now.Format(layout)
 
 	// 4. Apply processing
@@ -147,40 +209,70 @@ func (t *Target) IsStaticPath() bool {
 
 // generateLatestPath creates a "latest" version of a file path
 // For example: "./cache/data.json" -> "./cache/latest.json"
-//             "./cache/data-timestamp.json" -> "./cache/latest.json"
-//             "./cache/data.pp.json" -> "./cache/latest.pp.json"
+//
+//	"./cache/data-timestamp.json" -> "./cache/latest.json"
+//	"./cache/data.pp.json" -> "./cache/latest.pp.json"
+//	"./cache/data.json.gz" -> "./cache/latest.json.gz"
 func generateLatestPath(resolvedPath string) string {
-	dir := filepath.Dir(resolvedPath)
-	filename := filepath.Base(resolvedPath)
-	
+	// A compression extension, if present, is stripped before computing
+	// the base name and reappended at the end so it isn't mistaken for
+	// the file's real extension below.
+	compressionExt := compressionExtension(codecFromExtension(resolvedPath))
+	base := strings.TrimSuffix(resolvedPath, compressionExt)
+
+	dir := filepath.Dir(base)
+	filename := filepath.Base(base)
+
 	// Handle different file extension patterns
 	if strings.Contains(filename, ".pp.json") {
-		return filepath.Join(dir, "latest.pp.json")
+		return filepath.Join(dir, "latest.pp.json") + compressionExt
 	} else if strings.HasSuffix(filename, ".json") {
-		return filepath.Join(dir, "latest.json")
+		return filepath.Join(dir, "latest.json") + compressionExt
 	}
-	
+
 	// For other extensions, use the original logic
-	ext := filepath.Ext(resolvedPath)
+	ext := filepath.Ext(base)
 	if ext == "" {
-		return filepath.Join(dir, "latest")
+		return filepath.Join(dir, "latest") + compressionExt
 	}
-	
-	return filepath.Join(dir, "latest"+ext)
+
+	return filepath.Join(dir, "latest"+ext) + compressionExt
+}
+
+// cliFlags holds the parsed and validated command line flags.
+type cliFlags struct {
+	ConfigPath      string
+	JSONFormat      string
+	Latest          bool
+	Delay           int
+	Force           bool
+	Concurrency     int    // 0 means "use config.concurrency / the default"
+	LogLevel        string // "" means quiet (no console logger), matching the old non-verbose default
+	LogFormat       string // "text" or "json"
+	AllowMissingEnv bool   // substitute "" for unset $ENV_ vars instead of failing validation
+	Daemon          bool   // stay alive and run targets on their cron schedules instead of a single pass
+	NotifyOn        string // "" means "use config.notifications.notify_on"; else "failure" or "success"
 }
 
 // parseFlags parses and validates command line flags
-func parseFlags() (string, bool, string, bool, int) {
-	var configPath, jsonFormat string
-	var verbose, showVersion, latest bool
-	var delay int
+func parseFlags() cliFlags {
+	var configPath, jsonFormat, logLevel, logFormat, notifyOn string
+	var verbose, showVersion, latest, force, allowMissingEnv, daemon bool
+	var delay, concurrency int
 
 	flag.StringVar(&configPath, "config", "", "Path to YAML config file")
-	flag.BoolVar(&verbose, "v", false, "Enable verbose mode")
+	flag.BoolVar(&verbose, "v", false, "Enable verbose mode (alias for --log-level debug)")
+	flag.StringVar(&logLevel, "log-level", "", "Console log level: 'error', 'warn', 'info', 'debug', or 'trace'")
+	flag.StringVar(&logFormat, "log-format", "text", "Console/file log format: 'text' or 'json'")
 	flag.StringVar(&jsonFormat, "json-format", "original", "JSON formatting: 'original', 'pretty', 'minimized', or 'both'")
 	flag.BoolVar(&latest, "latest", false, "Create a 'latest' copy of each downloaded file")
 	flag.IntVar(&delay, "d", 0, "Delay in seconds between targets")
 	flag.IntVar(&delay, "delay", 0, "Delay in seconds between targets")
+	flag.BoolVar(&force, "force", false, "Bypass conditional GET caching and refetch every target")
+	flag.IntVar(&concurrency, "concurrency", 0, "Number of targets to fetch in parallel (overrides config, default 4)")
+	flag.BoolVar(&allowMissingEnv, "allow-missing-env", false, "Substitute empty strings for unset $ENV_ vars instead of failing (for CI dry-runs)")
+	flag.BoolVar(&daemon, "daemon", false, "Stay running and fetch each target on its cron schedule instead of a single pass")
+	flag.StringVar(&notifyOn, "notify-on", "", "Override notifications.notify_on: 'failure' or 'success' (e.g. for critical daily jobs that must be seen to run)")
 	flag.BoolVar(&showVersion, "version", false, "Show version information")
 	flag.Parse()
 
@@ -191,7 +283,12 @@ func parseFlags() (string, bool, string, bool, int) {
 
 	if configPath == "" {
 		fmt.Fprintf(os.Stderr, "Error: --config flag is required\n")
-		fmt.Fprintf(os.Stderr, "Usage: %s --config <yaml-file> [-v] [-d|--delay <seconds>] [--json-format original|pretty|minimized|both] [--latest] [--version]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Usage: %s --config <yaml-file> [-v] [--log-level error|warn|info|debug|trace] [--log-format text|json] [-d|--delay <seconds>] [--json-format original|pretty|minimized|both] [--latest] [--force] [--concurrency <n>] [--allow-missing-env] [--daemon] [--notify-on failure|success] [--version]\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	if notifyOn != "" && notifyOn != "failure" && notifyOn != "success" {
+		fmt.Fprintf(os.Stderr, "Error: --notify-on must be \"failure\" or \"success\"\n")
 		os.Exit(1)
 	}
 
@@ -200,38 +297,74 @@ func parseFlags() (string, bool, string, bool, int) {
 		os.Exit(1)
 	}
 
+	if concurrency < 0 {
+		fmt.Fprintf(os.Stderr, "Error: concurrency must be non-negative\n")
+		os.Exit(1)
+	}
+
+	// -v is a compatibility alias for --log-level debug, unless a more
+	// specific level was requested explicitly.
+	if logLevel == "" && verbose {
+		logLevel = "debug"
+	}
+	if logLevel != "" {
+		if _, err := parseLogLevel(logLevel); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	validFormats := []string{"original", "pretty", "minimized", "both"}
 	for _, format := range validFormats {
 		if jsonFormat == format {
-			return configPath, verbose, jsonFormat, latest, delay
+			return cliFlags{
+				ConfigPath:      configPath,
+				JSONFormat:      jsonFormat,
+				Latest:          latest,
+				Delay:           delay,
+				Force:           force,
+				Concurrency:     concurrency,
+				LogLevel:        logLevel,
+				LogFormat:       logFormat,
+				AllowMissingEnv: allowMissingEnv,
+				Daemon:          daemon,
+				NotifyOn:        notifyOn,
+			}
 		}
 	}
 
 	fmt.Fprintf(os.Stderr, "Error: --json-format must be one of: %s\n", strings.Join(validFormats, ", "))
 	os.Exit(1)
-	return "", false, "", false, 0 // unreachable
+	return cliFlags{} // unreachable
 }
 
-// loadConfig reads and parses the YAML configuration file
-func loadConfig(path string) (Config, error) {
+// loadConfig reads and parses the YAML configuration file, resolving
+// $ENV_ placeholders before validation so a missing variable is reported
+// as a config error rather than surfacing later as a broken URL or path.
+func loadConfig(path string, allowMissingEnv bool) (Config, []string, error) {
 	var config Config
 
 	configData, err := os.ReadFile(path)
 	if err != nil {
-		return config, fmt.Errorf("reading config file: %w", err)
+		return config, nil, fmt.Errorf("reading config file: %w", err)
 	}
 
 	err = yaml.Unmarshal(configData, &config)
 	if err != nil {
-		return config, fmt.Errorf("parsing YAML config: %w", err)
+		return config, nil, fmt.Errorf("parsing YAML config: %w", err)
+	}
+
+	warnings, err := interpolateConfig(&config, allowMissingEnv)
+	if err != nil {
+		return config, warnings, fmt.Errorf("resolving environment variables: %w", err)
 	}
 
 	// Validate config
 	if err := validateConfig(config); err != nil {
-		return config, fmt.Errorf("invalid config: %w", err)
+		return config, warnings, fmt.Errorf("invalid config: %w", err)
 	}
 
-	return config, nil
+	return config, warnings, nil
 }
 
 // validatePathConfig validates a path configuration (string or pattern-based)
@@ -313,6 +446,40 @@ func validateConfig(config Config) error {
 		return fmt.Errorf("no targets specified in config")
 	}
 
+	if config.MetaFormat != "" && config.MetaFormat != "json" {
+		return fmt.Errorf("meta_format %q is not supported (only \"json\")", config.MetaFormat)
+	}
+
+	if config.Concurrency < 0 {
+		return fmt.Errorf("concurrency must be non-negative")
+	}
+
+	if config.DefaultSchedule != "" {
+		if _, err := cronParser.Parse(config.DefaultSchedule); err != nil {
+			return fmt.Errorf("invalid default_schedule %q: %w", config.DefaultSchedule, err)
+		}
+	}
+
+	if config.Metrics.Enabled && config.Metrics.Listen != "" {
+		if _, _, err := net.SplitHostPort(config.Metrics.Listen); err != nil {
+			return fmt.Errorf("metrics: invalid listen address %q: %w", config.Metrics.Listen, err)
+		}
+	}
+
+	if err := validateNotifications(config.Notifications); err != nil {
+		return fmt.Errorf("notifications: %w", err)
+	}
+
+	if err := validateCompression(config.Compression); err != nil {
+		return fmt.Errorf("compression: %w", err)
+	}
+
+	if config.Storage.Driver != "" {
+		if _, err := storage.New(config.Storage.Driver, config.Storage.Settings); err != nil {
+			return fmt.Errorf("storage: %w", err)
+		}
+	}
+
 	for i, target := range config.Targets {
 		if target.URL == "" {
 			return fmt.Errorf("target %d: URL is required", i+1)
@@ -335,6 +502,49 @@ func validateConfig(config Config) error {
 		if _, err := parseHeaders(target.Headers); err != nil {
 			return fmt.Errorf("target %d: %w", i+1, err)
 		}
+
+		// Validate rate limit format
+		if target.RateLimit != "" {
+			if _, err := time.ParseDuration(target.RateLimit); err != nil {
+				return fmt.Errorf("target %d: invalid rate_limit %q: %w", i+1, target.RateLimit, err)
+			}
+		}
+
+		// Validate per-target storage override
+		if target.Storage != nil && target.Storage.Driver != "" {
+			if _, err := storage.New(target.Storage.Driver, target.Storage.Settings); err != nil {
+				return fmt.Errorf("target %d: storage: %w", i+1, err)
+			}
+		}
+
+		// Validate each transform pipeline step constructs cleanly
+		for j, spec := range target.Transforms {
+			if _, err := transform.New(spec.Name, spec.Arg); err != nil {
+				return fmt.Errorf("target %d: transforms[%d]: %w", i+1, j, err)
+			}
+		}
+
+		// Validate the per-target compression override, if any
+		if err := validateCompression(target.Compression); err != nil {
+			return fmt.Errorf("target %d: compression: %w", i+1, err)
+		}
+
+		// Validate the per-target cron schedule, if any
+		if target.Cron != "" {
+			if _, err := cronParser.Parse(target.Cron); err != nil {
+				return fmt.Errorf("target %d: invalid cron %q: %w", i+1, target.Cron, err)
+			}
+		}
+
+		// Validate TTL format and its state_file dependency
+		if target.TTL != "" {
+			if _, err := time.ParseDuration(target.TTL); err != nil {
+				return fmt.Errorf("target %d: invalid ttl %q: %w", i+1, target.TTL, err)
+			}
+			if config.StateFile == "" {
+				return fmt.Errorf("target %d: ttl requires a top-level state_file to be configured", i+1)
+			}
+		}
 	}
 
 	return nil
@@ -365,47 +575,56 @@ func parseHeaders(headerStrings []string) (http.Header, error) {
 }
 
 // setupLoggers creates and configures file and console loggers
-func setupLoggers(config Config, verbose bool) (*slog.Logger, *slog.Logger, func(), error) {
+func setupLoggers(config Config, flags cliFlags) (*slog.Logger, *slog.Logger, func(), error) {
 	var fileLogger *slog.Logger
 	var consoleLogger *slog.Logger
 	var cleanup func()
 
-	// Setup file logger
+	// Setup file logger; the file is rotated by date, and re-rotates itself
+	// across midnight so a long --daemon run doesn't keep writing to the
+	// day it started on.
 	if config.LogFile != "" {
-		logDir := filepath.Dir(config.LogFile)
-		if err := os.MkdirAll(logDir, 0755); err != nil {
-			return nil, nil, nil, fmt.Errorf("creating log directory %q: %w", logDir, err)
+		writer, err := newRotatingFileWriter(config.LogFile, time.Now())
+		if err != nil {
+			return nil, nil, nil, err
 		}
 
-		logFile, err := os.OpenFile(config.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		handler, err := newHandler(writer, flags.LogFormat, slog.LevelWarn)
 		if err != nil {
-			return nil, nil, nil, fmt.Errorf("opening log file %q: %w", config.LogFile, err)
+			return nil, nil, nil, err
 		}
-
-		fileLogger = slog.New(slog.NewTextHandler(logFile, &slog.HandlerOptions{
-			Level: slog.LevelWarn,
-		}))
-
-		cleanup = func() { logFile.Close() }
+		fileLogger = slog.New(handler)
+		cleanup = func() { writer.Close() }
 	} else {
-		fileLogger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-			Level: slog.LevelWarn,
-		}))
+		handler, err := newHandler(os.Stderr, flags.LogFormat, slog.LevelWarn)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		fileLogger = slog.New(handler)
 		cleanup = func() {}
 	}
 
-	// Setup console logger
-	if verbose {
-		consoleLogger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-			Level: slog.LevelDebug,
-		}))
+	// Setup console logger, if a level was requested via -v or --log-level
+	if flags.LogLevel != "" {
+		level, err := parseLogLevel(flags.LogLevel)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		handler, err := newHandler(os.Stdout, flags.LogFormat, level)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		consoleLogger = slog.New(handler)
 	}
 
 	return fileLogger, consoleLogger, cleanup, nil
 }
 
-// formatJSON handles JSON formatting based on the specified format
-func formatJSON(data []byte, format string, targetPath string, latest bool) ([]byte, string, error) {
+// formatJSON handles JSON formatting based on the specified format. The
+// "both" format also writes the pretty companion (and its latest mirror)
+// through store, since those are side outputs rather than the primary
+// return value.
+func formatJSON(data []byte, format string, targetPath string, latest bool, store storage.Storage, codec string) ([]byte, string, error) {
 	// Skip formatting if not JSON or format is original
 	if !strings.HasSuffix(strings.ToLower(targetPath), ".json") || format == "original" {
 		return data, "", nil
@@ -426,15 +645,18 @@ func formatJSON(data []byte, format string, targetPath string, latest bool) ([]b
 		return formatted, "minimized", err
 
 	case "both":
-		return formatJSONBoth(jsonData, targetPath, latest)
+		return formatJSONBoth(jsonData, targetPath, latest, store, codec)
 
 	default:
 		return data, "", fmt.Errorf("unknown format: %s", format)
 	}
 }
 
-// formatJSONBoth creates both minimized and pretty-printed versions
-func formatJSONBoth(jsonData any, targetPath string, latest bool) ([]byte, string, error) {
+// formatJSONBoth creates both minimized and pretty-printed versions. The
+// pretty companion (and its latest mirror) are written here directly, so
+// they're compressed with codec the same way the primary file is by the
+// caller.
+func formatJSONBoth(jsonData any, targetPath string, latest bool, store storage.Storage, codec string) ([]byte, string, error) {
 	minimized, err := json.Marshal(jsonData)
 	if err != nil {
 		return nil, "", err
@@ -445,15 +667,19 @@ func formatJSONBoth(jsonData any, targetPath string, latest bool) ([]byte, strin
 		return minimized, "minimized", nil
 	}
 
-	prettyPath := strings.TrimSuffix(targetPath, ".json") + ".pp.json"
-	if err := writeFileWithDir(prettyPath, pretty); err != nil {
+	prettyData, err := compressBytes(codec, pretty)
+	if err != nil {
+		return minimized, "minimized", fmt.Errorf("compressing pretty file: %w", err)
+	}
+	prettyPath := strings.TrimSuffix(targetPath, ".json") + ".pp.json" + compressionExtension(codec)
+	if err := store.Put(prettyPath, prettyData, nil); err != nil {
 		return minimized, "minimized", fmt.Errorf("writing pretty file: %w", err)
 	}
 
 	// If latest flag is set, also create latest.pp.json
 	if latest {
 		latestPrettyPath := generateLatestPath(prettyPath)
-		if err := writeFileWithDir(latestPrettyPath, pretty); err != nil {
+		if err := store.Latest(prettyPath, latestPrettyPath); err != nil {
 			return minimized, "minimized", fmt.Errorf("writing latest pretty file: %w", err)
 		}
 	}
@@ -471,29 +697,129 @@ func writeFileWithDir(path string, data []byte) error {
 	return os.WriteFile(path, data, 0644)
 }
 
+// runTransforms applies a target's transform pipeline in order, each
+// step receiving the previous step's output.
+func runTransforms(target Target, data []byte, store storage.Storage, resolvedPath string, codec string) ([]byte, error) {
+	ctx := transform.Context{
+		TargetName:   target.Name,
+		ResolvedPath: resolvedPath,
+		Store:        store,
+		Codec:        codec,
+		Compress:     compressBytes,
+		CodecExt:     compressionExtension,
+	}
+	for _, spec := range target.Transforms {
+		t, err := transform.New(spec.Name, spec.Arg)
+		if err != nil {
+			return data, fmt.Errorf("transform %q: %w", spec.Name, err)
+		}
+		data, err = t.Apply(data, ctx)
+		if err != nil {
+			return data, fmt.Errorf("transform %q: %w", spec.Name, err)
+		}
+	}
+	return data, nil
+}
+
 // processTarget processes a single target
-func processTarget(target Target, client *retryablehttp.Client, jsonFormat string, latest bool, fileLogger, consoleLogger *slog.Logger) error {
+// resolveStorage builds the Storage backend for a target: its own
+// "storage" override if set, otherwise the config's default, falling back
+// to the "fs" driver so existing configs keep writing to disk unchanged.
+func resolveStorage(config Config, target Target) (storage.Storage, error) {
+	sc := config.Storage
+	if target.Storage != nil && target.Storage.Driver != "" {
+		sc = *target.Storage
+	}
+	if sc.Driver == "" {
+		sc.Driver = "fs"
+	}
+	return storage.New(sc.Driver, sc.Settings)
+}
+
+// resolveTargetStores builds every target's Storage once, up front, so a
+// long-running --daemon doesn't construct (and leak) a fresh S3/GCS/Redis
+// client on every cron fire of the same target.
+//
+// A target whose storage fails to resolve is reported in errs rather than
+// aborting the whole batch, so one misconfigured target doesn't prevent
+// every other target from running.
+func resolveTargetStores(config Config) (stores map[string]storage.Storage, errs map[string]error) {
+	stores = make(map[string]storage.Storage, len(config.Targets))
+	errs = make(map[string]error, len(config.Targets))
+	for _, target := range config.Targets {
+		store, err := resolveStorage(config, target)
+		if err != nil {
+			errs[target.Name] = err
+			continue
+		}
+		stores[target.Name] = store
+	}
+	return stores, errs
+}
+
+// Outcomes reported by processTarget, used to build the end-of-run summary.
+const (
+	statusFetched = "fetched"
+	statusCached  = "cached"
+	statusSkipped = "skipped"
+	statusFailed  = "failed"
+)
+
+// runOptions bundles the settings that are constant across a run (as
+// opposed to Target, which varies per call) so processTarget doesn't grow
+// an unbounded parameter list as features are added.
+type runOptions struct {
+	JSONFormat    string
+	Latest        bool
+	Force         bool
+	TraceEnabled  bool
+	CacheDir      string
+	DumpDir       string
+	Limiter       *hostLimiter
+	State         *stateStore
+	FileLogger    *slog.Logger
+	ConsoleLogger *slog.Logger
+	Metrics       *metricsRecorder
+	Notifier      *notifier
+	Compression   string // default codec used when a target has no override; see resolveCompression
+}
+
+func processTarget(target Target, client *retryablehttp.Client, store storage.Storage, opts runOptions) (string, error) {
+	fileLogger, consoleLogger := opts.FileLogger, opts.ConsoleLogger
+
 	// Resolve path first
 	resolvedPath, err := target.GetResolvedPath()
 	if err != nil {
-		return fmt.Errorf("resolving path: %w", err)
+		return statusFailed, fmt.Errorf("resolving path: %w", err)
 	}
 
 	if consoleLogger != nil {
 		consoleLogger.Info("Processing target", "name", target.Name, "url", target.URL, "path", resolvedPath)
 	}
 
+	// Skip entirely if this target's TTL hasn't elapsed yet.
+	if target.TTL != "" && !opts.Force && opts.State != nil {
+		ttl, _ := time.ParseDuration(target.TTL)
+		if entry, ok := opts.State.Get(target.Name); ok && time.Since(entry.LastFetch) < ttl {
+			if consoleLogger != nil {
+				consoleLogger.Info("Skipping target, TTL not elapsed", "name", target.Name, "last_fetch", entry.LastFetch, "ttl", target.TTL)
+			}
+			opts.Metrics.ObserveFetch(target.Name, statusSkipped, 0)
+			return statusSkipped, nil
+		}
+	}
+
 	// Create HTTP request
 	req, err := retryablehttp.NewRequest("GET", target.URL, nil)
 	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
+		return statusFailed, fmt.Errorf("creating request: %w", err)
 	}
 
 	// Set custom headers if specified
 	if len(target.Headers) > 0 {
 		customHeaders, err := parseHeaders(target.Headers)
 		if err != nil {
-			return fmt.Errorf("parsing headers: %w", err)
+			return statusFailed, fmt.Errorf("parsing headers: %w", err)
 		}
 
 		// Copy custom headers to the request
@@ -508,50 +834,153 @@ func processTarget(target Target, client *retryablehttp.Client, jsonFormat strin
 		}
 	}
 
+	// Attach conditional validators from a previous run, unless the target
+	// opts out or a refetch was forced on the command line.
+	metaPath := cacheMetaPath(resolvedPath, opts.CacheDir)
+	var cachedMeta *CacheMeta
+	if !opts.Force && !target.NoCache {
+		cachedMeta, err = loadCacheMeta(store, opts.CacheDir, resolvedPath)
+		if err != nil {
+			fileLogger.Warn("Could not read cache metadata, fetching without validators", "path", metaPath, "error", err)
+		}
+		applyConditionalHeaders(req, cachedMeta)
+	}
+
+	// Respect this target's per-host rate limit before making the request.
+	if opts.Limiter != nil {
+		minInterval, _ := time.ParseDuration(target.RateLimit)
+		opts.Limiter.Wait(targetHost(target.URL), minInterval)
+	}
+
 	// Fetch data
+	tracker := withAttemptTracker(req)
+	start := time.Now()
 	resp, err := client.Do(req)
+	elapsed := time.Since(start)
 	if err != nil {
-		return fmt.Errorf("fetching URL: %w", err)
+		opts.Metrics.ObserveFetch(target.Name, statusFailed, elapsed)
+		return statusFailed, fmt.Errorf("fetching URL: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if consoleLogger != nil {
+		consoleLogger.Info("Fetch complete", "target", target.Name, "status", resp.StatusCode, "retries", tracker.attempt, "elapsed", elapsed)
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		if consoleLogger != nil {
+			consoleLogger.Info("Cache hit, not modified", "name", target.Name, "path", resolvedPath)
+		}
+		if opts.TraceEnabled {
+			if err := dumpRequestResponse(opts.DumpDir, target.Name, req.Request, resp, nil); err != nil {
+				fileLogger.Warn("Could not write trace dump", "target", target.Name, "error", err)
+			}
+		}
+		// A 304 can arrive even without validators (cachedMeta is nil when
+		// --force/NoCache skipped loading it, or loadCacheMeta failed), if
+		// a misbehaving origin or an intermediary cache decides to send one
+		// anyway. Fall back to a fresh CacheMeta rather than deref a nil.
+		if cachedMeta == nil {
+			cachedMeta = &CacheMeta{}
+		}
+		cachedMeta.FetchedAt = time.Now()
+		if err := saveCacheMeta(store, opts.CacheDir, resolvedPath, *cachedMeta); err != nil {
+			fileLogger.Warn("Could not refresh cache metadata", "path", metaPath, "error", err)
+			opts.Metrics.IncWriteError(target.Name, "cache_meta")
+		}
+		if opts.Latest {
+			cachedPath := resolvedPath + compressionExtension(resolveCompression(target, opts))
+			latestPath := generateLatestPath(cachedPath)
+			if err := store.Latest(cachedPath, latestPath); err != nil {
+				fileLogger.Warn("Failed to refresh latest file", "path", latestPath, "error", err)
+				opts.Metrics.IncWriteError(target.Name, "latest")
+			}
+		}
+		if opts.State != nil {
+			prevEntry, _ := opts.State.Get(target.Name)
+			if err := opts.State.Update(target.Name, StateEntry{LastFetch: time.Now(), ContentHash: prevEntry.ContentHash}); err != nil {
+				fileLogger.Warn("Could not update state file", "name", target.Name, "error", err)
+				opts.Metrics.IncWriteError(target.Name, "state")
+			}
+		}
+		opts.Metrics.ObserveFetch(target.Name, statusCached, elapsed)
+		opts.Metrics.SetLastSuccess(target.Name)
+		return statusCached, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("received status %d", resp.StatusCode)
+		opts.Metrics.ObserveFetch(target.Name, statusFailed, elapsed)
+		return statusFailed, fmt.Errorf("received status %d", resp.StatusCode)
 	}
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("reading response body: %w", err)
+		return statusFailed, fmt.Errorf("reading response body: %w", err)
 	}
 
 	if consoleLogger != nil {
 		consoleLogger.Info("Successfully fetched data", "bytes", len(bodyBytes))
 	}
 
-	// Format JSON if needed
-	dataToWrite, formatDesc, err := formatJSON(bodyBytes, jsonFormat, resolvedPath, latest)
-	if err != nil {
-		fileLogger.Warn("Could not format JSON, using original", "path", resolvedPath, "error", err)
-		dataToWrite = bodyBytes
-	} else if formatDesc != "" && consoleLogger != nil {
-		consoleLogger.Info("Formatted JSON", "format", formatDesc)
+	if opts.TraceEnabled {
+		if err := dumpRequestResponse(opts.DumpDir, target.Name, req.Request, resp, bodyBytes); err != nil {
+			fileLogger.Warn("Could not write trace dump", "target", target.Name, "error", err)
+		}
+	}
+
+	// Compression is applied after JSON formatting (and any transforms) so
+	// pretty/minimized/both remain meaningful, and before the write so the
+	// compressed extension lands on both the primary file and its latest
+	// mirror.
+	codec := resolveCompression(target, opts)
+
+	// Run the target's transform pipeline if it has one, otherwise fall
+	// back to the legacy --json-format handling.
+	var dataToWrite []byte
+	if len(target.Transforms) > 0 {
+		dataToWrite, err = runTransforms(target, bodyBytes, store, resolvedPath, codec)
+		if err != nil {
+			fileLogger.Warn("Could not apply transforms, using original", "path", resolvedPath, "error", err)
+			dataToWrite = bodyBytes
+		} else if consoleLogger != nil {
+			consoleLogger.Info("Applied transforms", "count", len(target.Transforms))
+		}
+	} else {
+		var formatDesc string
+		dataToWrite, formatDesc, err = formatJSON(bodyBytes, opts.JSONFormat, resolvedPath, opts.Latest, store, codec)
+		if err != nil {
+			fileLogger.Warn("Could not format JSON, using original", "path", resolvedPath, "error", err)
+			dataToWrite = bodyBytes
+		} else if formatDesc != "" && consoleLogger != nil {
+			consoleLogger.Info("Formatted JSON", "format", formatDesc)
+		}
+	}
+
+	writePath := resolvedPath
+	if compressed, err := compressBytes(codec, dataToWrite); err != nil {
+		fileLogger.Warn("Could not compress output, writing uncompressed", "codec", codec, "error", err)
+	} else {
+		dataToWrite = compressed
+		writePath = resolvedPath + compressionExtension(codec)
 	}
 
-	// Write file
-	if err := writeFileWithDir(resolvedPath, dataToWrite); err != nil {
-		return fmt.Errorf("writing file: %w", err)
+	// Write the fetched artifact through the target's storage backend
+	if err := store.Put(writePath, dataToWrite, nil); err != nil {
+		opts.Metrics.ObserveFetch(target.Name, statusFailed, elapsed)
+		return statusFailed, fmt.Errorf("writing file: %w", err)
 	}
 
 	if consoleLogger != nil {
-		consoleLogger.Info("Successfully wrote file", "path", resolvedPath)
+		consoleLogger.Info("Successfully wrote file", "path", writePath)
 	}
 
 	// Write latest file if flag is set
-	if latest {
-		latestPath := generateLatestPath(resolvedPath)
-		if err := writeFileWithDir(latestPath, dataToWrite); err != nil {
+	if opts.Latest {
+		latestPath := generateLatestPath(writePath)
+		if err := store.Latest(writePath, latestPath); err != nil {
 			// Log warning but don't fail the entire operation
 			fileLogger.Warn("Failed to write latest file", "path", latestPath, "error", err)
+			opts.Metrics.IncWriteError(target.Name, "latest")
 			if consoleLogger != nil {
 				consoleLogger.Warn("Failed to write latest file", "path", latestPath, "error", err)
 			}
@@ -560,22 +989,86 @@ func processTarget(target Target, client *retryablehttp.Client, jsonFormat strin
 		}
 	}
 
-	return nil
+	// Record validators for the next run's conditional GET, unless caching
+	// is disabled for this target.
+	if !target.NoCache {
+		meta := cacheMetaFromResponse(resp, int64(len(bodyBytes)))
+		if err := saveCacheMeta(store, opts.CacheDir, resolvedPath, meta); err != nil {
+			fileLogger.Warn("Could not write cache metadata", "path", metaPath, "error", err)
+			opts.Metrics.IncWriteError(target.Name, "cache_meta")
+		}
+	}
+
+	// Update TTL state with this fetch's content hash, noting when a
+	// refetch turned out to be byte-for-byte identical to the last one.
+	if opts.State != nil {
+		hash := contentHash(bodyBytes)
+		if prevEntry, ok := opts.State.Get(target.Name); ok && prevEntry.ContentHash == hash {
+			if consoleLogger != nil {
+				consoleLogger.Info("Unchanged", "name", target.Name, "path", resolvedPath)
+			}
+		}
+		if err := opts.State.Update(target.Name, StateEntry{LastFetch: time.Now(), ContentHash: hash}); err != nil {
+			fileLogger.Warn("Could not update state file", "name", target.Name, "error", err)
+			opts.Metrics.IncWriteError(target.Name, "state")
+		}
+	}
+
+	opts.Metrics.ObserveFetch(target.Name, statusFetched, elapsed)
+	opts.Metrics.ObserveBytes(target.Name, len(bodyBytes))
+	opts.Metrics.SetLastSuccess(target.Name)
+	return statusFetched, nil
+}
+
+// runSummary tallies how each target's fetch was resolved, printed once
+// the whole run completes.
+type runSummary struct {
+	Fetched int
+	Cached  int
+	Skipped int
+	Failed  int
+}
+
+// resolveConcurrency picks the worker pool size: CLI flag wins, then the
+// config field, then defaultConcurrency.
+func resolveConcurrency(flagValue, configValue int) int {
+	if flagValue > 0 {
+		return flagValue
+	}
+	if configValue > 0 {
+		return configValue
+	}
+	return defaultConcurrency
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "decompress" {
+		if err := runDecompress(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Parse command line flags
-	configPath, verbose, jsonFormat, latest, delay := parseFlags()
+	flags := parseFlags()
 
 	// Load configuration
-	config, err := loadConfig(configPath)
+	config, envWarnings, err := loadConfig(flags.ConfigPath, flags.AllowMissingEnv)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
+	for _, warning := range envWarnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+
+	if flags.NotifyOn != "" {
+		config.Notifications.NotifyOn = flags.NotifyOn
+	}
 
 	// Setup loggers
-	fileLogger, consoleLogger, cleanup, err := setupLoggers(config, verbose)
+	fileLogger, consoleLogger, cleanup, err := setupLoggers(config, flags)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error setting up loggers: %v\n", err)
 		os.Exit(1)
@@ -583,37 +1076,152 @@ func main() {
 	defer cleanup()
 
 	if consoleLogger != nil {
-		consoleLogger.Info("Reading config file", "path", configPath)
+		consoleLogger.Info("Reading config file", "path", flags.ConfigPath)
 		consoleLogger.Info("Found targets to process", "count", len(config.Targets))
 	}
 
 	// Create HTTP client
 	retryClient := retryablehttp.NewClient()
 	retryClient.RetryMax = 3
-	if !verbose {
+	if consoleLogger == nil {
 		retryClient.Logger = nil
 	}
+	traceEnabled := consoleLogger != nil && consoleLogger.Enabled(context.Background(), levelTrace)
+	retryClient.RequestLogHook = attemptLogHook
 
-	// Process each target
-	for i, target := range config.Targets {
+	concurrency := resolveConcurrency(flags.Concurrency, config.Concurrency)
+	if consoleLogger != nil {
+		consoleLogger.Info("Starting worker pool", "concurrency", concurrency)
+	}
+
+	initialState, err := loadState(config.StateFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading state file: %v\n", err)
+		os.Exit(1)
+	}
+
+	var metrics *metricsRecorder
+	var metricsServer *http.Server
+	if config.Metrics.Enabled {
+		registry := prometheus.NewRegistry()
+		metrics = newMetricsRecorder(registry)
+		metricsServer = startMetricsServer(config.Metrics, registry)
 		if consoleLogger != nil {
-			consoleLogger.Info("Processing target", "index", i+1, "total", len(config.Targets))
+			consoleLogger.Info("Metrics server listening", "listen", config.Metrics.Listen, "path", config.Metrics.Path)
 		}
+	}
+
+	opts := runOptions{
+		JSONFormat:    flags.JSONFormat,
+		Latest:        flags.Latest,
+		Force:         flags.Force,
+		TraceEnabled:  traceEnabled,
+		CacheDir:      config.CacheDir,
+		DumpDir:       config.DumpDir,
+		Limiter:       newHostLimiter(),
+		State:         newStateStore(config.StateFile, initialState),
+		FileLogger:    fileLogger,
+		ConsoleLogger: consoleLogger,
+		Metrics:       metrics,
+		Notifier:      newNotifier(config.Notifications),
+		Compression:   config.Compression,
+	}
 
-		if err := processTarget(target, retryClient, jsonFormat, latest, fileLogger, consoleLogger); err != nil {
-			fileLogger.Error("Failed to process target", "name", target.Name, "url", target.URL, "error", err)
+	if flags.Daemon {
+		if err := runDaemon(config, retryClient, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running daemon: %v\n", err)
+			os.Exit(1)
 		}
+		shutdownMetricsServer(metricsServer)
+		return
+	}
 
-		// Add delay between targets (but not after the last one)
-		if delay > 0 && i < len(config.Targets)-1 {
-			if consoleLogger != nil {
-				consoleLogger.Info("Waiting before next target", "delay_seconds", delay)
+	// Resolve each target's Storage once up front (rather than inside the
+	// per-job hot path) so one-shot and --daemon mode share the same
+	// build-once-reuse-across-runs discipline.
+	stores, storeErrs := resolveTargetStores(config)
+
+	jobs := make(chan int)
+	results := make(chan string)
+
+	var failuresMu sync.Mutex
+	var failures []targetFailure
+
+	var workers sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for idx := range jobs {
+				target := config.Targets[idx]
+				if consoleLogger != nil {
+					consoleLogger.Info("Processing target", "index", idx+1, "total", len(config.Targets), "name", target.Name, "url", target.URL)
+				}
+
+				store, ok := stores[target.Name]
+				if !ok {
+					err := storeErrs[target.Name]
+					fileLogger.Error("Failed to set up storage for target", "name", target.Name, "error", err)
+					failuresMu.Lock()
+					failures = append(failures, targetFailure{Name: target.Name, URL: target.URL, Err: err, At: time.Now()})
+					failuresMu.Unlock()
+					results <- statusFailed
+					continue
+				}
+
+				status, err := processTarget(target, retryClient, store, opts)
+				if err != nil {
+					fileLogger.Error("Failed to process target", "name", target.Name, "url", target.URL, "error", err)
+					failuresMu.Lock()
+					failures = append(failures, targetFailure{Name: target.Name, URL: target.URL, Err: err, At: time.Now()})
+					failuresMu.Unlock()
+				}
+				results <- status
+
+				if flags.Delay > 0 {
+					time.Sleep(time.Duration(flags.Delay) * time.Second)
+				}
 			}
-			time.Sleep(time.Duration(delay) * time.Second)
+		}()
+	}
+
+	go func() {
+		for i := range config.Targets {
+			jobs <- i
+		}
+		close(jobs)
+	}()
+
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	var summary runSummary
+	for status := range results {
+		switch status {
+		case statusFetched:
+			summary.Fetched++
+		case statusCached:
+			summary.Cached++
+		case statusSkipped:
+			summary.Skipped++
+		default:
+			summary.Failed++
 		}
 	}
 
+	opts.Notifier.NotifyFailures(failures)
+	if len(failures) == 0 && opts.Notifier.notifyOnSuccess() {
+		opts.Notifier.NotifySuccess(fmt.Sprintf("fetched=%d cached=%d skipped=%d failed=%d", summary.Fetched, summary.Cached, summary.Skipped, summary.Failed))
+	}
+
 	if consoleLogger != nil {
 		consoleLogger.Info("Application finished successfully!")
 	}
+	fmt.Printf("Summary: fetched=%d cached=%d skipped=%d failed=%d\n", summary.Fetched, summary.Cached, summary.Skipped, summary.Failed)
+
+	// One-shot mode: all targets are done, so the metrics server has
+	// nothing left to scrape. (Daemon mode keeps it running instead.)
+	shutdownMetricsServer(metricsServer)
 }
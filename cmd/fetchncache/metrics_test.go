@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsRecorderObserveFetch(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics := newMetricsRecorder(registry)
+
+	metrics.ObserveFetch("feed", statusFetched, 250*time.Millisecond)
+	metrics.ObserveBytes("feed", 1024)
+	metrics.IncWriteError("feed", "latest")
+	metrics.SetLastSuccess("feed")
+
+	if got := testutil.ToFloat64(metrics.fetchTotal.WithLabelValues("feed", statusFetched)); got != 1 {
+		t.Errorf("fetch_total = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.writeErrors.WithLabelValues("feed", "latest")); got != 1 {
+		t.Errorf("write_errors_total = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.lastSuccess.WithLabelValues("feed")); got == 0 {
+		t.Errorf("last_success timestamp was not set")
+	}
+}
+
+func TestMetricsRecorderNilIsSafe(t *testing.T) {
+	var metrics *metricsRecorder
+	metrics.ObserveFetch("feed", statusFailed, time.Second)
+	metrics.ObserveBytes("feed", 10)
+	metrics.IncWriteError("feed", "state")
+	metrics.SetLastSuccess("feed")
+}
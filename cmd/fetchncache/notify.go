@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NotificationsConfig configures optional alerts fired when targets fail,
+// so --daemon runs left unattended don't fail silently. Setting NotifyOn
+// to "success" additionally sends one message per clean run, for jobs
+// where silence itself would be suspicious.
+type NotificationsConfig struct {
+	NotifyOn string             `yaml:"notify_on,omitempty"` // "failure" (default) or "success"
+	Webhook  *WebhookSinkConfig `yaml:"webhook,omitempty"`
+	SMTP     *SMTPSinkConfig    `yaml:"smtp,omitempty"`
+}
+
+// WebhookSinkConfig POSTs a JSON payload compatible with Slack/Discord/
+// generic incoming webhooks.
+type WebhookSinkConfig struct {
+	URL         string `yaml:"url"`
+	MinInterval string `yaml:"min_interval,omitempty"` // e.g. "15m"; suppresses repeat sends within the window
+}
+
+// SMTPSinkConfig emails the batched notification via net/smtp.
+type SMTPSinkConfig struct {
+	Host        string   `yaml:"host"`
+	Port        int      `yaml:"port"`
+	User        string   `yaml:"user,omitempty"`
+	Pass        string   `yaml:"pass,omitempty"`
+	From        string   `yaml:"from,omitempty"` // defaults to User when empty
+	To          []string `yaml:"to"`
+	MinInterval string   `yaml:"min_interval,omitempty"`
+}
+
+// targetFailure is one target's failed run, as reported to the notifier.
+type targetFailure struct {
+	Name string
+	URL  string
+	Err  error
+	At   time.Time
+}
+
+// notifier batches a run's failures into a single alert per configured
+// sink, throttled by each sink's min_interval so a flapping target
+// doesn't flood a channel.
+type notifier struct {
+	cfg NotificationsConfig
+
+	mu          sync.Mutex
+	lastWebhook time.Time
+	lastSMTP    time.Time
+}
+
+func newNotifier(cfg NotificationsConfig) *notifier {
+	return &notifier{cfg: cfg}
+}
+
+// validateNotifications checks NotifyOn and the configured sinks at
+// config-load time, before any target has run.
+func validateNotifications(cfg NotificationsConfig) error {
+	if cfg.NotifyOn != "" && cfg.NotifyOn != "failure" && cfg.NotifyOn != "success" {
+		return fmt.Errorf("notify_on %q must be \"failure\" or \"success\"", cfg.NotifyOn)
+	}
+
+	if cfg.Webhook != nil {
+		if cfg.Webhook.URL == "" {
+			return fmt.Errorf("webhook requires a %q setting", "url")
+		}
+		if err := validateMinInterval(cfg.Webhook.MinInterval); err != nil {
+			return fmt.Errorf("webhook: %w", err)
+		}
+	}
+
+	if cfg.SMTP != nil {
+		if cfg.SMTP.Host == "" {
+			return fmt.Errorf("smtp requires a %q setting", "host")
+		}
+		if cfg.SMTP.Port == 0 {
+			return fmt.Errorf("smtp requires a %q setting", "port")
+		}
+		if len(cfg.SMTP.To) == 0 {
+			return fmt.Errorf("smtp requires at least one %q recipient", "to")
+		}
+		if err := validateMinInterval(cfg.SMTP.MinInterval); err != nil {
+			return fmt.Errorf("smtp: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func validateMinInterval(minInterval string) error {
+	if minInterval == "" {
+		return nil
+	}
+	if _, err := time.ParseDuration(minInterval); err != nil {
+		return fmt.Errorf("invalid min_interval %q: %w", minInterval, err)
+	}
+	return nil
+}
+
+// notifyOnSuccess reports whether a message should also be sent when a
+// run (or target, in --daemon mode) completes without failures.
+func (n *notifier) notifyOnSuccess() bool {
+	return n != nil && n.cfg.NotifyOn == "success"
+}
+
+// NotifyFailures sends one batched message across all configured sinks
+// for the given failures, subject to each sink's throttle.
+func (n *notifier) NotifyFailures(failures []targetFailure) {
+	if n == nil || len(failures) == 0 {
+		return
+	}
+	subject := fmt.Sprintf("fetchncache: %d target(s) failed", len(failures))
+	n.send(subject, formatFailures(failures))
+}
+
+// NotifySuccess sends a single informational message, used in
+// --notify-on success mode for jobs that must be seen to run.
+func (n *notifier) NotifySuccess(message string) {
+	if n == nil {
+		return
+	}
+	n.send("fetchncache: success", message)
+}
+
+func formatFailures(failures []targetFailure) string {
+	var b strings.Builder
+	for _, f := range failures {
+		fmt.Fprintf(&b, "[%s] %s (%s): %v\n", f.At.Format(time.RFC3339), f.Name, f.URL, f.Err)
+	}
+	return b.String()
+}
+
+func (n *notifier) send(subject, body string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.cfg.Webhook != nil && throttleOK(&n.lastWebhook, n.cfg.Webhook.MinInterval) {
+		if err := sendWebhook(*n.cfg.Webhook, subject, body); err != nil {
+			fmt.Fprintf(os.Stderr, "notify: webhook failed: %v\n", err)
+		}
+	}
+	if n.cfg.SMTP != nil && throttleOK(&n.lastSMTP, n.cfg.SMTP.MinInterval) {
+		if err := sendSMTPMessage(*n.cfg.SMTP, subject, body); err != nil {
+			fmt.Fprintf(os.Stderr, "notify: smtp failed: %v\n", err)
+		}
+	}
+}
+
+// throttleOK reports whether minInterval has elapsed since *last,
+// updating *last when it has (or when no interval is configured).
+func throttleOK(last *time.Time, minInterval string) bool {
+	if minInterval == "" {
+		*last = time.Now()
+		return true
+	}
+	d, err := time.ParseDuration(minInterval)
+	if err != nil || time.Since(*last) >= d {
+		*last = time.Now()
+		return true
+	}
+	return false
+}
+
+// webhookPayload carries both the Slack-style "text" field and the
+// Discord-style "content" field so one payload works against either
+// without per-sink configuration.
+type webhookPayload struct {
+	Text    string `json:"text"`
+	Content string `json:"content"`
+}
+
+func sendWebhook(cfg WebhookSinkConfig, subject, body string) error {
+	message := subject + "\n" + body
+	data, err := json.Marshal(webhookPayload{Text: message, Content: message})
+	if err != nil {
+		return fmt.Errorf("encoding payload: %w", err)
+	}
+
+	resp, err := http.Post(cfg.URL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("posting to %s: %w", cfg.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sendSMTPMessage sends subject/body as a plain-text email. from falls
+// back to the auth username when unset, mirroring the pattern from the
+// mail utility this was modeled on.
+func sendSMTPMessage(cfg SMTPSinkConfig, subject, body string) error {
+	if len(cfg.To) == 0 {
+		return fmt.Errorf("smtp sink has no recipients")
+	}
+
+	from := cfg.From
+	if from == "" {
+		from = cfg.User
+	}
+
+	var auth smtp.Auth
+	if cfg.User != "" {
+		auth = smtp.PlainAuth("", cfg.User, cfg.Pass, cfg.Host)
+	}
+
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n",
+		from, strings.Join(cfg.To, ", "), subject, body)
+
+	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+	return smtp.SendMail(addr, auth, from, cfg.To, []byte(msg))
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestThrottleOKNoInterval(t *testing.T) {
+	var last time.Time
+	if !throttleOK(&last, "") {
+		t.Error("throttleOK with no min_interval should always allow a send")
+	}
+	if last.IsZero() {
+		t.Error("throttleOK should still update *last when unthrottled")
+	}
+}
+
+func TestThrottleOKSuppressesWithinInterval(t *testing.T) {
+	last := time.Now()
+	if throttleOK(&last, "15m") {
+		t.Error("throttleOK should suppress a second send within min_interval")
+	}
+}
+
+func TestThrottleOKAllowsAfterInterval(t *testing.T) {
+	last := time.Now().Add(-20 * time.Minute)
+	if !throttleOK(&last, "15m") {
+		t.Error("throttleOK should allow a send once min_interval has elapsed")
+	}
+}
+
+func TestThrottleOKInvalidIntervalFailsOpen(t *testing.T) {
+	var last time.Time
+	if !throttleOK(&last, "not-a-duration") {
+		t.Error("throttleOK should treat an invalid min_interval as unthrottled rather than blocking forever")
+	}
+}
+
+func TestValidateNotifications(t *testing.T) {
+	if err := validateNotifications(NotificationsConfig{}); err != nil {
+		t.Errorf("empty NotificationsConfig should be valid: %v", err)
+	}
+
+	if err := validateNotifications(NotificationsConfig{NotifyOn: "bogus"}); err == nil {
+		t.Error("an invalid notify_on should be rejected")
+	}
+
+	if err := validateNotifications(NotificationsConfig{Webhook: &WebhookSinkConfig{}}); err == nil {
+		t.Error("a webhook sink without a url should be rejected")
+	}
+
+	if err := validateNotifications(NotificationsConfig{SMTP: &SMTPSinkConfig{Host: "smtp.example.com", Port: 587}}); err == nil {
+		t.Error("an smtp sink without recipients should be rejected")
+	}
+}
+
+func TestNotifyOnSuccess(t *testing.T) {
+	var nilNotifier *notifier
+	if nilNotifier.notifyOnSuccess() {
+		t.Error("a nil notifier should never report notifyOnSuccess")
+	}
+
+	if newNotifier(NotificationsConfig{}).notifyOnSuccess() {
+		t.Error("default notify_on (failure) should report notifyOnSuccess = false")
+	}
+	if !newNotifier(NotificationsConfig{NotifyOn: "success"}).notifyOnSuccess() {
+		t.Error("notify_on: success should report notifyOnSuccess = true")
+	}
+}
+
+func TestNotifierSendsWebhookOnFailure(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := make([]byte, 4096)
+		n, _ := r.Body.Read(buf)
+		received = string(buf[:n])
+	}))
+	defer server.Close()
+
+	n := newNotifier(NotificationsConfig{Webhook: &WebhookSinkConfig{URL: server.URL}})
+	n.NotifyFailures([]targetFailure{{Name: "feed", URL: "https://example.com", Err: errTest, At: time.Now()}})
+
+	if received == "" {
+		t.Fatal("webhook should have received a POST body")
+	}
+}
+
+func TestNotifierThrottlesRepeatedFailures(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer server.Close()
+
+	n := newNotifier(NotificationsConfig{Webhook: &WebhookSinkConfig{URL: server.URL, MinInterval: "1h"}})
+	failure := []targetFailure{{Name: "feed", URL: "https://example.com", Err: errTest, At: time.Now()}}
+
+	n.NotifyFailures(failure)
+	n.NotifyFailures(failure)
+
+	if hits != 1 {
+		t.Errorf("webhook received %d requests, want 1 (the second send should be throttled)", hits)
+	}
+}
+
+var errTest = &testError{"boom"}
+
+type testError struct{ msg string }
+
+func (e *testError) Error() string { return e.msg }
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/url"
+	"sync"
+	"time"
+)
+
+// hostLimiter enforces a minimum delay between requests to the same host,
+// so fanning a worker pool out across many targets on one origin doesn't
+// hammer it. It is safe for concurrent use.
+type hostLimiter struct {
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+// newHostLimiter creates an empty limiter.
+func newHostLimiter() *hostLimiter {
+	return &hostLimiter{next: make(map[string]time.Time)}
+}
+
+// Wait blocks, if necessary, until it is this host's turn under minInterval.
+// A zero or negative minInterval disables limiting for the call.
+func (h *hostLimiter) Wait(host string, minInterval time.Duration) {
+	if minInterval <= 0 {
+		return
+	}
+
+	h.mu.Lock()
+	now := time.Now()
+	wait := time.Duration(0)
+	if due, ok := h.next[host]; ok && due.After(now) {
+		wait = due.Sub(now)
+	}
+	h.next[host] = now.Add(wait).Add(minInterval)
+	h.mu.Unlock()
+
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// targetHost extracts the host used to key the rate limiter for a target URL.
+// Unparsable URLs fall back to the raw string so they still get limited
+// against themselves rather than panicking.
+func targetHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return u.Host
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHostLimiterDisabledForZeroInterval(t *testing.T) {
+	h := newHostLimiter()
+	start := time.Now()
+	h.Wait("example.com", 0)
+	h.Wait("example.com", -1)
+	if time.Since(start) > 50*time.Millisecond {
+		t.Error("Wait with a zero or negative interval should not block")
+	}
+}
+
+func TestHostLimiterSerializesSameHost(t *testing.T) {
+	h := newHostLimiter()
+	const interval = 50 * time.Millisecond
+
+	start := time.Now()
+	h.Wait("example.com", interval)
+	h.Wait("example.com", interval)
+	h.Wait("example.com", interval)
+	elapsed := time.Since(start)
+
+	if elapsed < 2*interval {
+		t.Errorf("three calls at %s spacing took %s, want at least %s", interval, elapsed, 2*interval)
+	}
+}
+
+func TestHostLimiterDoesNotThrottleDifferentHosts(t *testing.T) {
+	h := newHostLimiter()
+	const interval = time.Hour
+
+	start := time.Now()
+	h.Wait("a.example.com", interval)
+	h.Wait("b.example.com", interval)
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("distinct hosts should not block each other, took %s", elapsed)
+	}
+}
+
+func TestHostLimiterConcurrentUse(t *testing.T) {
+	h := newHostLimiter()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.Wait("example.com", time.Millisecond)
+		}()
+	}
+	wg.Wait() // must not race or deadlock
+}
+
+func TestTargetHost(t *testing.T) {
+	cases := map[string]string{
+		"https://example.com/data.json": "example.com",
+		"http://example.com:8080/x":      "example.com:8080",
+		"not a url":                      "not a url",
+	}
+	for url, want := range cases {
+		if got := targetHost(url); got != want {
+			t.Errorf("targetHost(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
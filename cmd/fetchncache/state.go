@@ -0,0 +1,89 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// StateEntry records enough about a target's last successful fetch to
+// decide whether its TTL has elapsed and whether the content changed.
+type StateEntry struct {
+	LastFetch   time.Time `json:"last_fetch"`
+	ContentHash string    `json:"content_hash,omitempty"`
+}
+
+// State is keyed by target name.
+type State map[string]StateEntry
+
+// loadState reads the state file. A missing file just means this is the
+// first run, so it returns an empty State rather than an error.
+func loadState(path string) (State, error) {
+	if path == "" {
+		return State{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return State{}, nil
+		}
+		return nil, fmt.Errorf("reading state file %q: %w", path, err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing state file %q: %w", path, err)
+	}
+	return state, nil
+}
+
+// saveState writes the state file, creating its directory if needed.
+func saveState(path string, state State) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding state: %w", err)
+	}
+	return writeFileWithDir(path, data)
+}
+
+// stateStore guards the shared State map and its backing file so the
+// worker pool can read and update it concurrently.
+type stateStore struct {
+	mu    sync.Mutex
+	path  string
+	state State
+}
+
+func newStateStore(path string, state State) *stateStore {
+	return &stateStore{path: path, state: state}
+}
+
+// Get returns the recorded entry for a target, if any.
+func (s *stateStore) Get(targetName string) (StateEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.state[targetName]
+	return entry, ok
+}
+
+// Update sets a target's entry and persists the whole state file.
+func (s *stateStore) Update(targetName string, entry StateEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state[targetName] = entry
+	return saveState(s.path, s.state)
+}
+
+// contentHash returns the hex-encoded SHA-256 of data.
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
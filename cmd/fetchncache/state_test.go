@@ -0,0 +1,92 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadStateNoPath(t *testing.T) {
+	state, err := loadState("")
+	if err != nil {
+		t.Fatalf("loadState: %v", err)
+	}
+	if len(state) != 0 {
+		t.Errorf("loadState(\"\") = %+v, want empty State", state)
+	}
+}
+
+func TestLoadStateMissingFile(t *testing.T) {
+	state, err := loadState(filepath.Join(t.TempDir(), "absent.json"))
+	if err != nil {
+		t.Fatalf("loadState: %v", err)
+	}
+	if len(state) != 0 {
+		t.Errorf("loadState on a missing file = %+v, want empty State", state)
+	}
+}
+
+func TestSaveAndLoadStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	want := State{"feed": StateEntry{LastFetch: time.Now().Truncate(time.Second), ContentHash: "abc123"}}
+
+	if err := saveState(path, want); err != nil {
+		t.Fatalf("saveState: %v", err)
+	}
+
+	got, err := loadState(path)
+	if err != nil {
+		t.Fatalf("loadState: %v", err)
+	}
+	entry, ok := got["feed"]
+	if !ok {
+		t.Fatal("loadState did not return the saved \"feed\" entry")
+	}
+	if entry.ContentHash != want["feed"].ContentHash || !entry.LastFetch.Equal(want["feed"].LastFetch) {
+		t.Errorf("loadState entry = %+v, want %+v", entry, want["feed"])
+	}
+}
+
+func TestStateStoreGetUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	store := newStateStore(path, State{})
+
+	if _, ok := store.Get("feed"); ok {
+		t.Fatal("Get on an empty store should report no entry")
+	}
+
+	entry := StateEntry{LastFetch: time.Now(), ContentHash: "deadbeef"}
+	if err := store.Update("feed", entry); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	got, ok := store.Get("feed")
+	if !ok {
+		t.Fatal("Get after Update should report the entry")
+	}
+	if got.ContentHash != entry.ContentHash {
+		t.Errorf("ContentHash = %q, want %q", got.ContentHash, entry.ContentHash)
+	}
+
+	// Update persists to disk too, not just the in-memory map.
+	onDisk, err := loadState(path)
+	if err != nil {
+		t.Fatalf("loadState: %v", err)
+	}
+	if onDisk["feed"].ContentHash != entry.ContentHash {
+		t.Errorf("on-disk ContentHash = %q, want %q", onDisk["feed"].ContentHash, entry.ContentHash)
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	a := contentHash([]byte("hello"))
+	b := contentHash([]byte("hello"))
+	c := contentHash([]byte("world"))
+
+	if a != b {
+		t.Error("contentHash is not deterministic for identical input")
+	}
+	if a == c {
+		t.Error("contentHash should differ for different input")
+	}
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func init() {
+	Register("fs", newFSStorage)
+}
+
+// fsStorage is the default backend: it writes straight to the local
+// filesystem, creating parent directories as needed. This preserves the
+// tool's original behavior before Storage existed.
+type fsStorage struct{}
+
+func newFSStorage(settings map[string]string) (Storage, error) {
+	return fsStorage{}, nil
+}
+
+func (fsStorage) Put(key string, data []byte, meta map[string]string) error {
+	if err := os.MkdirAll(filepath.Dir(key), 0755); err != nil {
+		return fmt.Errorf("creating directory for %q: %w", key, err)
+	}
+	if err := os.WriteFile(key, data, 0644); err != nil {
+		return fmt.Errorf("writing %q: %w", key, err)
+	}
+
+	if len(meta) == 0 {
+		return nil
+	}
+	metaBytes, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("encoding metadata for %q: %w", key, err)
+	}
+	return os.WriteFile(key+".storagemeta.json", metaBytes, 0644)
+}
+
+func (fsStorage) Get(key string) ([]byte, map[string]string, error) {
+	data, err := os.ReadFile(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading %q: %w", key, err)
+	}
+
+	meta := map[string]string{}
+	if metaBytes, err := os.ReadFile(key + ".storagemeta.json"); err == nil {
+		if err := json.Unmarshal(metaBytes, &meta); err != nil {
+			return data, nil, fmt.Errorf("decoding metadata for %q: %w", key, err)
+		}
+	}
+	return data, meta, nil
+}
+
+func (fsStorage) Exists(key string) (bool, error) {
+	_, err := os.Stat(key)
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+func (fsStorage) Delete(key string) error {
+	if err := os.Remove(key); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("deleting %q: %w", key, err)
+	}
+	os.Remove(key + ".storagemeta.json") // best effort
+	return nil
+}
+
+func (f fsStorage) Latest(srcKey, destKey string) error {
+	data, meta, err := f.Get(srcKey)
+	if err != nil {
+		return fmt.Errorf("reading %q for latest copy: %w", srcKey, err)
+	}
+	return f.Put(destKey, data, meta)
+}
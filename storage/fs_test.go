@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFSStoragePutGet(t *testing.T) {
+	store, err := New("fs", nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "nested", "out.json")
+	if err := store.Put(path, []byte(`{"x":1}`), map[string]string{"content-type": "application/json"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	data, meta, err := store.Get(path)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(data) != `{"x":1}` {
+		t.Errorf("data = %q, want %q", data, `{"x":1}`)
+	}
+	if meta["content-type"] != "application/json" {
+		t.Errorf("meta[content-type] = %q, want %q", meta["content-type"], "application/json")
+	}
+}
+
+func TestFSStorageExistsAndDelete(t *testing.T) {
+	store, err := New("fs", nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "out.json")
+	if exists, _ := store.Exists(path); exists {
+		t.Error("Exists on a never-written path should be false")
+	}
+
+	if err := store.Put(path, []byte("1"), nil); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if exists, err := store.Exists(path); err != nil || !exists {
+		t.Errorf("Exists after Put = %v, %v, want true, nil", exists, err)
+	}
+
+	if err := store.Delete(path); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if exists, _ := store.Exists(path); exists {
+		t.Error("Exists after Delete should be false")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected %q to be removed from disk", path)
+	}
+}
+
+func TestFSStorageDeleteMissingIsNotError(t *testing.T) {
+	store, err := New("fs", nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := store.Delete(filepath.Join(t.TempDir(), "absent")); err != nil {
+		t.Errorf("Delete on a missing path should not error, got %v", err)
+	}
+}
+
+func TestFSStorageLatest(t *testing.T) {
+	store, err := New("fs", nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.json")
+	dest := filepath.Join(dir, "latest.json")
+
+	if err := store.Put(src, []byte("payload"), map[string]string{"k": "v"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := store.Latest(src, dest); err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+
+	data, meta, err := store.Get(dest)
+	if err != nil {
+		t.Fatalf("Get(dest): %v", err)
+	}
+	if string(data) != "payload" {
+		t.Errorf("dest data = %q, want %q", data, "payload")
+	}
+	if meta["k"] != "v" {
+		t.Errorf("dest meta[k] = %q, want %q", meta["k"], "v")
+	}
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	gcs "cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+)
+
+func init() {
+	Register("gcs", newGCSStorage)
+}
+
+// gcsStorage writes artifacts to a Google Cloud Storage bucket. Keys are
+// used as-is for object names, so a target's `path` becomes the object's
+// full name within the bucket.
+type gcsStorage struct {
+	client *gcs.Client
+	bucket string
+}
+
+// newGCSStorage builds the client from the target/global storage settings
+// map: "bucket" (required), "credentials_file" (path to a service account
+// JSON key; falls back to Application Default Credentials when unset).
+func newGCSStorage(settings map[string]string) (Storage, error) {
+	bucket := settings["bucket"]
+	if bucket == "" {
+		return nil, fmt.Errorf("storage: gcs requires a %q setting", "bucket")
+	}
+
+	var opts []option.ClientOption
+	if credentialsFile := settings["credentials_file"]; credentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(credentialsFile))
+	}
+
+	client, err := gcs.NewClient(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("storage: gcs: creating client: %w", err)
+	}
+
+	return &gcsStorage{client: client, bucket: bucket}, nil
+}
+
+func (g *gcsStorage) object(key string) *gcs.ObjectHandle {
+	return g.client.Bucket(g.bucket).Object(key)
+}
+
+func (g *gcsStorage) Put(key string, data []byte, meta map[string]string) error {
+	ctx := context.Background()
+	w := g.object(key).NewWriter(ctx)
+	w.Metadata = meta
+
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return fmt.Errorf("storage: gcs: putting %q: %w", key, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("storage: gcs: putting %q: %w", key, err)
+	}
+	return nil
+}
+
+func (g *gcsStorage) Get(key string) ([]byte, map[string]string, error) {
+	ctx := context.Background()
+	obj := g.object(key)
+
+	r, err := obj.NewReader(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("storage: gcs: getting %q: %w", key, err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("storage: gcs: reading %q: %w", key, err)
+	}
+
+	attrs, err := obj.Attrs(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("storage: gcs: reading metadata for %q: %w", key, err)
+	}
+	return data, attrs.Metadata, nil
+}
+
+func (g *gcsStorage) Exists(key string) (bool, error) {
+	_, err := g.object(key).Attrs(context.Background())
+	if err != nil {
+		if errors.Is(err, gcs.ErrObjectNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("storage: gcs: checking %q: %w", key, err)
+	}
+	return true, nil
+}
+
+func (g *gcsStorage) Delete(key string) error {
+	if err := g.object(key).Delete(context.Background()); err != nil {
+		return fmt.Errorf("storage: gcs: deleting %q: %w", key, err)
+	}
+	return nil
+}
+
+func (g *gcsStorage) Latest(srcKey, destKey string) error {
+	ctx := context.Background()
+	_, err := g.object(destKey).CopierFrom(g.object(srcKey)).Run(ctx)
+	if err != nil {
+		return fmt.Errorf("storage: gcs: copying %q to %q: %w", srcKey, destKey, err)
+	}
+	return nil
+}
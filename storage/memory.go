@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+)
+
+func init() {
+	Register("memory", newMemoryStorage)
+}
+
+// memoryStorage keeps everything in an in-process map. Useful for tests
+// and for dry-running a config without touching disk or a remote service.
+type memoryStorage struct {
+	mu    sync.RWMutex
+	data  map[string][]byte
+	metas map[string]map[string]string
+}
+
+func newMemoryStorage(settings map[string]string) (Storage, error) {
+	return &memoryStorage{
+		data:  make(map[string][]byte),
+		metas: make(map[string]map[string]string),
+	}, nil
+}
+
+func (m *memoryStorage) Put(key string, data []byte, meta map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = append([]byte(nil), data...)
+	if meta != nil {
+		m.metas[key] = meta
+	}
+	return nil
+}
+
+func (m *memoryStorage) Get(key string) ([]byte, map[string]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.data[key]
+	if !ok {
+		return nil, nil, fmt.Errorf("memory storage: key %q not found", key)
+	}
+	return data, m.metas[key], nil
+}
+
+func (m *memoryStorage) Exists(key string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.data[key]
+	return ok, nil
+}
+
+func (m *memoryStorage) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, key)
+	delete(m.metas, key)
+	return nil
+}
+
+func (m *memoryStorage) Latest(srcKey, destKey string) error {
+	data, meta, err := m.Get(srcKey)
+	if err != nil {
+		return fmt.Errorf("reading %q for latest copy: %w", srcKey, err)
+	}
+	return m.Put(destKey, data, meta)
+}
@@ -0,0 +1,97 @@
+package storage
+
+import "testing"
+
+func TestMemoryStoragePutGet(t *testing.T) {
+	store, err := New("memory", nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := store.Put("a/b.json", []byte(`{"x":1}`), map[string]string{"content-type": "application/json"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	data, meta, err := store.Get("a/b.json")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(data) != `{"x":1}` {
+		t.Errorf("data = %q, want %q", data, `{"x":1}`)
+	}
+	if meta["content-type"] != "application/json" {
+		t.Errorf("meta[content-type] = %q, want %q", meta["content-type"], "application/json")
+	}
+}
+
+func TestMemoryStorageGetMissing(t *testing.T) {
+	store, err := New("memory", nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, _, err := store.Get("absent"); err == nil {
+		t.Error("Get on a missing key should return an error")
+	}
+}
+
+func TestMemoryStorageExistsAndDelete(t *testing.T) {
+	store, err := New("memory", nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if exists, _ := store.Exists("a"); exists {
+		t.Error("Exists on a never-written key should be false")
+	}
+
+	if err := store.Put("a", []byte("1"), nil); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if exists, err := store.Exists("a"); err != nil || !exists {
+		t.Errorf("Exists after Put = %v, %v, want true, nil", exists, err)
+	}
+
+	if err := store.Delete("a"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if exists, _ := store.Exists("a"); exists {
+		t.Error("Exists after Delete should be false")
+	}
+}
+
+func TestMemoryStorageLatest(t *testing.T) {
+	store, err := New("memory", nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := store.Put("src", []byte("payload"), map[string]string{"k": "v"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := store.Latest("src", "dest"); err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+
+	data, meta, err := store.Get("dest")
+	if err != nil {
+		t.Fatalf("Get(dest): %v", err)
+	}
+	if string(data) != "payload" {
+		t.Errorf("dest data = %q, want %q", data, "payload")
+	}
+	if meta["k"] != "v" {
+		t.Errorf("dest meta[k] = %q, want %q", meta["k"], "v")
+	}
+}
+
+func TestMemoryStorageLatestMissingSource(t *testing.T) {
+	store, err := New("memory", nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := store.Latest("absent", "dest"); err == nil {
+		t.Error("Latest from a missing source key should return an error")
+	}
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func init() {
+	Register("redis", newRedisStorage)
+}
+
+// redisStorage stores each artifact as one Redis string plus, if it has
+// metadata, a companion "<key>:meta" JSON string.
+type redisStorage struct {
+	client *redis.Client
+}
+
+// newRedisStorage builds the client from settings: "addr" (required,
+// "host:port"), "password", "db".
+func newRedisStorage(settings map[string]string) (Storage, error) {
+	addr := settings["addr"]
+	if addr == "" {
+		return nil, fmt.Errorf("storage: redis requires an %q setting", "addr")
+	}
+
+	db := 0
+	if dbSetting := settings["db"]; dbSetting != "" {
+		if _, err := fmt.Sscanf(dbSetting, "%d", &db); err != nil {
+			return nil, fmt.Errorf("storage: redis: invalid db %q: %w", dbSetting, err)
+		}
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: settings["password"],
+		DB:       db,
+	})
+	return &redisStorage{client: client}, nil
+}
+
+func metaKey(key string) string { return key + ":meta" }
+
+func (r *redisStorage) Put(key string, data []byte, meta map[string]string) error {
+	ctx := context.Background()
+	if err := r.client.Set(ctx, key, data, 0).Err(); err != nil {
+		return fmt.Errorf("storage: redis: setting %q: %w", key, err)
+	}
+	if len(meta) == 0 {
+		return nil
+	}
+	metaBytes, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("storage: redis: encoding metadata for %q: %w", key, err)
+	}
+	if err := r.client.Set(ctx, metaKey(key), metaBytes, 0).Err(); err != nil {
+		return fmt.Errorf("storage: redis: setting metadata for %q: %w", key, err)
+	}
+	return nil
+}
+
+func (r *redisStorage) Get(key string) ([]byte, map[string]string, error) {
+	ctx := context.Background()
+	data, err := r.client.Get(ctx, key).Bytes()
+	if err != nil {
+		return nil, nil, fmt.Errorf("storage: redis: getting %q: %w", key, err)
+	}
+
+	meta := map[string]string{}
+	if metaBytes, err := r.client.Get(ctx, metaKey(key)).Bytes(); err == nil {
+		if err := json.Unmarshal(metaBytes, &meta); err != nil {
+			return data, nil, fmt.Errorf("storage: redis: decoding metadata for %q: %w", key, err)
+		}
+	}
+	return data, meta, nil
+}
+
+func (r *redisStorage) Exists(key string) (bool, error) {
+	n, err := r.client.Exists(context.Background(), key).Result()
+	if err != nil {
+		return false, fmt.Errorf("storage: redis: checking %q: %w", key, err)
+	}
+	return n > 0, nil
+}
+
+func (r *redisStorage) Delete(key string) error {
+	ctx := context.Background()
+	if err := r.client.Del(ctx, key, metaKey(key)).Err(); err != nil {
+		return fmt.Errorf("storage: redis: deleting %q: %w", key, err)
+	}
+	return nil
+}
+
+func (r *redisStorage) Latest(srcKey, destKey string) error {
+	ctx := context.Background()
+	if err := r.client.Copy(ctx, srcKey, destKey, 0, true).Err(); err != nil {
+		return fmt.Errorf("storage: redis: copying %q to %q: %w", srcKey, destKey, err)
+	}
+	if exists, _ := r.Exists(metaKey(srcKey)); exists {
+		_ = r.client.Copy(ctx, metaKey(srcKey), metaKey(destKey), 0, true).Err()
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func init() {
+	Register("s3", newS3Storage)
+}
+
+// s3Storage writes artifacts to an S3-compatible object store (AWS S3,
+// MinIO, R2, ...). Keys are used as-is for object keys, so a target's
+// `path` becomes the object's full path within the bucket.
+type s3Storage struct {
+	client *s3.Client
+	bucket string
+}
+
+// newS3Storage builds the client from the target/global storage settings
+// map: "bucket" (required), "region", "endpoint" (for S3-compatible
+// non-AWS services), "access_key_id", "secret_access_key".
+func newS3Storage(settings map[string]string) (Storage, error) {
+	bucket := settings["bucket"]
+	if bucket == "" {
+		return nil, fmt.Errorf("storage: s3 requires a %q setting", "bucket")
+	}
+
+	var opts []func(*awsconfig.LoadOptions) error
+	if region := settings["region"]; region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+	if accessKey, secretKey := settings["access_key_id"], settings["secret_access_key"]; accessKey != "" && secretKey != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("storage: s3: loading AWS config: %w", err)
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint := settings["endpoint"]; endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		}
+	})
+
+	return &s3Storage{client: client, bucket: bucket}, nil
+}
+
+func (s *s3Storage) Put(key string, data []byte, meta map[string]string) error {
+	_, err := s.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(key),
+		Body:     bytes.NewReader(data),
+		Metadata: meta,
+	})
+	if err != nil {
+		return fmt.Errorf("storage: s3: putting %q: %w", key, err)
+	}
+	return nil
+}
+
+func (s *s3Storage) Get(key string) ([]byte, map[string]string, error) {
+	out, err := s.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("storage: s3: getting %q: %w", key, err)
+	}
+	defer out.Body.Close()
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("storage: s3: reading %q: %w", key, err)
+	}
+	return data, out.Metadata, nil
+}
+
+func (s *s3Storage) Exists(key string) (bool, error) {
+	_, err := s.client.HeadObject(context.Background(), &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("storage: s3: checking %q: %w", key, err)
+	}
+	return true, nil
+}
+
+func (s *s3Storage) Delete(key string) error {
+	_, err := s.client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("storage: s3: deleting %q: %w", key, err)
+	}
+	return nil
+}
+
+func (s *s3Storage) Latest(srcKey, destKey string) error {
+	_, err := s.client.CopyObject(context.Background(), &s3.CopyObjectInput{
+		Bucket:     aws.String(s.bucket),
+		Key:        aws.String(destKey),
+		CopySource: aws.String(s.bucket + "/" + srcKey),
+	})
+	if err != nil {
+		return fmt.Errorf("storage: s3: copying %q to %q: %w", srcKey, destKey, err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// Package storage defines a pluggable backend for where fetched artifacts
+// get written. A Target's resolved path is treated as an opaque key, so
+// the same config can target a local directory, an object store, or a
+// key/value cache without the fetch loop knowing the difference.
+package storage
+
+import "fmt"
+
+// Storage is implemented by every output backend. Keys are the resolved
+// target path (for fs-like backends) or an object/cache key (for
+// everything else) — the caller never needs to know which.
+type Storage interface {
+	// Put writes data under key, along with optional metadata (e.g.
+	// content-type) for backends that support storing it out of band.
+	Put(key string, data []byte, meta map[string]string) error
+
+	// Get reads back data and any metadata previously stored under key.
+	Get(key string) ([]byte, map[string]string, error)
+
+	// Exists reports whether key has been written.
+	Exists(key string) (bool, error)
+
+	// Delete removes key. Deleting a key that doesn't exist is not an error.
+	Delete(key string) error
+
+	// Latest mirrors the artifact already stored under srcKey to destKey,
+	// so a target's "--latest" copy and pretty companion work the same
+	// way regardless of backend (a file copy, an S3 CopyObject, a Redis
+	// COPY, ...). srcKey must already exist.
+	Latest(srcKey, destKey string) error
+}
+
+// Factory builds a Storage from driver-specific settings (as parsed from
+// the YAML `storage:` block).
+type Factory func(settings map[string]string) (Storage, error)
+
+var adapters = make(map[string]Factory)
+
+// Register makes a driver available under name. It panics on duplicate
+// registration, the same way database/sql and beego/cache's driver
+// registries do — it indicates a programming error, not a runtime one.
+func Register(name string, factory Factory) {
+	if _, exists := adapters[name]; exists {
+		panic(fmt.Sprintf("storage: driver %q already registered", name))
+	}
+	adapters[name] = factory
+}
+
+// New constructs the named driver with the given settings.
+func New(name string, settings map[string]string) (Storage, error) {
+	factory, ok := adapters[name]
+	if !ok {
+		return nil, fmt.Errorf("storage: unknown driver %q", name)
+	}
+	return factory(settings)
+}
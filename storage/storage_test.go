@@ -0,0 +1,18 @@
+package storage
+
+import "testing"
+
+func TestNewUnknownDriver(t *testing.T) {
+	if _, err := New("bogus", nil); err == nil {
+		t.Error("New with an unregistered driver should return an error")
+	}
+}
+
+func TestRegisterPanicsOnDuplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register should panic on a duplicate driver name")
+		}
+	}()
+	Register("fs", newFSStorage)
+}
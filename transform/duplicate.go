@@ -0,0 +1,47 @@
+package transform
+
+import "fmt"
+
+func init() {
+	Register("duplicate", func(arg string) (Transformer, error) {
+		if arg == "" {
+			return nil, fmt.Errorf("transform: duplicate requires a destination path argument")
+		}
+		return duplicate{path: arg}, nil
+	})
+}
+
+// duplicate writes the untouched input to a second path via ctx.Store,
+// alongside the target's primary output, then passes the bytes through.
+// The side output is compressed with the target's resolved codec (and
+// its path gets the matching extension), so it doesn't look out of
+// place next to a compressed primary file.
+type duplicate struct {
+	path string
+}
+
+func (duplicate) Name() string { return "duplicate" }
+
+func (d duplicate) Apply(in []byte, ctx Context) ([]byte, error) {
+	if ctx.Store == nil {
+		return in, fmt.Errorf("transform: duplicate requires a storage backend")
+	}
+
+	out := in
+	path := d.path
+	if ctx.Compress != nil {
+		compressed, err := ctx.Compress(ctx.Codec, in)
+		if err != nil {
+			return in, fmt.Errorf("transform: duplicate: compressing %q: %w", d.path, err)
+		}
+		out = compressed
+	}
+	if ctx.CodecExt != nil {
+		path += ctx.CodecExt(ctx.Codec)
+	}
+
+	if err := ctx.Store.Put(path, out, nil); err != nil {
+		return in, fmt.Errorf("transform: duplicate: writing %q: %w", path, err)
+	}
+	return in, nil
+}
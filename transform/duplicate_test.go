@@ -0,0 +1,87 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/BorisAnthony/go-fetchncache/storage"
+)
+
+func TestDuplicateRequiresArg(t *testing.T) {
+	if _, err := New("duplicate", ""); err == nil {
+		t.Error("New(\"duplicate\", \"\") should require a destination path argument")
+	}
+}
+
+func TestDuplicateWritesSideOutputAndPassesThrough(t *testing.T) {
+	tr, err := New("duplicate", "copy.json")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	store, err := storage.New("memory", nil)
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+
+	in := []byte(`{"a":1}`)
+	out, err := tr.Apply(in, Context{Store: store})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if string(out) != string(in) {
+		t.Errorf("Apply should pass the input through unchanged, got %q want %q", out, in)
+	}
+
+	data, _, err := store.Get("copy.json")
+	if err != nil {
+		t.Fatalf("Get(copy.json): %v", err)
+	}
+	if string(data) != string(in) {
+		t.Errorf("side output = %q, want %q", data, in)
+	}
+}
+
+func TestDuplicateRequiresStore(t *testing.T) {
+	tr, err := New("duplicate", "copy.json")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, err := tr.Apply([]byte("x"), Context{}); err == nil {
+		t.Error("Apply without a Store should return an error")
+	}
+}
+
+func TestDuplicateCompressesSideOutput(t *testing.T) {
+	tr, err := New("duplicate", "copy")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	store, err := storage.New("memory", nil)
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+
+	ctx := Context{
+		Store: store,
+		Codec: "gzip",
+		Compress: func(codec string, data []byte) ([]byte, error) {
+			return append([]byte("compressed:"), data...), nil
+		},
+		CodecExt: func(codec string) string { return ".gz" },
+	}
+
+	in := []byte("payload")
+	if _, err := tr.Apply(in, ctx); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	data, _, err := store.Get("copy.gz")
+	if err != nil {
+		t.Fatalf("Get(copy.gz): %v", err)
+	}
+	if string(data) != "compressed:payload" {
+		t.Errorf("side output = %q, want %q", data, "compressed:payload")
+	}
+}
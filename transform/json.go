@@ -0,0 +1,36 @@
+package transform
+
+import "encoding/json"
+
+func init() {
+	Register("json_pretty", func(arg string) (Transformer, error) { return jsonPretty{}, nil })
+	Register("json_minify", func(arg string) (Transformer, error) { return jsonMinify{}, nil })
+}
+
+// jsonPretty re-indents a JSON payload, porting the old --json-format
+// pretty behavior.
+type jsonPretty struct{}
+
+func (jsonPretty) Name() string { return "json_pretty" }
+
+func (jsonPretty) Apply(in []byte, _ Context) ([]byte, error) {
+	var data any
+	if err := json.Unmarshal(in, &data); err != nil {
+		return in, err
+	}
+	return json.MarshalIndent(data, "", "  ")
+}
+
+// jsonMinify strips insignificant whitespace, porting the old
+// --json-format minimized behavior.
+type jsonMinify struct{}
+
+func (jsonMinify) Name() string { return "json_minify" }
+
+func (jsonMinify) Apply(in []byte, _ Context) ([]byte, error) {
+	var data any
+	if err := json.Unmarshal(in, &data); err != nil {
+		return in, err
+	}
+	return json.Marshal(data)
+}
@@ -0,0 +1,60 @@
+// Package transform defines a pluggable pipeline for reshaping a fetched
+// response before it's written out. Each Target can list transforms to
+// run in order, replacing the single hardcoded --json-format special case.
+package transform
+
+import (
+	"fmt"
+
+	"github.com/BorisAnthony/go-fetchncache/storage"
+)
+
+// Context carries the per-target information a Transformer may need:
+// where the primary output is headed, a Storage handle for transforms
+// that write additional, named outputs alongside it, and the target's
+// resolved compression codec so a side output can match the primary
+// file's on-disk format.
+type Context struct {
+	TargetName   string
+	ResolvedPath string
+	Store        storage.Storage
+
+	// Codec is the target's resolved compression codec ("", "none",
+	// "gzip", "zstd", or "lz4"). Compress and CodecExt are nil-safe; a
+	// Transformer that writes a side output should call them so the
+	// result matches the primary file's compression.
+	Codec    string
+	Compress func(codec string, data []byte) ([]byte, error)
+	CodecExt func(codec string) string
+}
+
+// Transformer reshapes response bytes. It may also produce side outputs
+// (via ctx.Store) and simply pass the input through unchanged.
+type Transformer interface {
+	Name() string
+	Apply(in []byte, ctx Context) ([]byte, error)
+}
+
+// Factory builds a Transformer from its (optional) YAML argument, e.g. the
+// jq filter in `transforms: [{jq: ".items[]"}]`.
+type Factory func(arg string) (Transformer, error)
+
+var registry = make(map[string]Factory)
+
+// Register makes a transform available under name. Like storage.Register,
+// it panics on duplicate registration since that's a programming error.
+func Register(name string, factory Factory) {
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("transform: %q already registered", name))
+	}
+	registry[name] = factory
+}
+
+// New constructs the named transform with the given argument.
+func New(name, arg string) (Transformer, error) {
+	factory, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("transform: unknown transform %q", name)
+	}
+	return factory(arg)
+}
@@ -0,0 +1,64 @@
+package transform
+
+import "testing"
+
+func TestNewUnknownTransform(t *testing.T) {
+	if _, err := New("bogus", ""); err == nil {
+		t.Error("New with an unregistered transform should return an error")
+	}
+}
+
+func TestRegisterPanicsOnDuplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register should panic on a duplicate transform name")
+		}
+	}()
+	Register("json_pretty", func(arg string) (Transformer, error) { return jsonPretty{}, nil })
+}
+
+func TestJSONPretty(t *testing.T) {
+	tr, err := New("json_pretty", "")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	out, err := tr.Apply([]byte(`{"b":1,"a":2}`), Context{})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	// json.Marshal on a decoded map always sorts keys alphabetically,
+	// regardless of the original input's key order.
+	want := "{\n  \"a\": 2,\n  \"b\": 1\n}"
+	if string(out) != want {
+		t.Errorf("Apply = %q, want %q", out, want)
+	}
+}
+
+func TestJSONPrettyInvalidInput(t *testing.T) {
+	tr, err := New("json_pretty", "")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, err := tr.Apply([]byte("not json"), Context{}); err == nil {
+		t.Error("Apply on invalid JSON should return an error")
+	}
+}
+
+func TestJSONMinify(t *testing.T) {
+	tr, err := New("json_minify", "")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	out, err := tr.Apply([]byte("{\n  \"a\": 1\n}"), Context{})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	if want := `{"a":1}`; string(out) != want {
+		t.Errorf("Apply = %q, want %q", out, want)
+	}
+}